@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/git-l10n/git-po-helper/util"
+)
+
+type agentRunCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *agentRunCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "agent-run",
+		Short:         "Run agent to work on po files",
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			util.EnableGateSummary()
+		},
+	}
+	v.cmd.PersistentFlags().String("agent-cmd",
+		"",
+		"agent command template, with placeholder {source}")
+	v.cmd.PersistentFlags().String("log-dir",
+		"",
+		"write agent output and diagnostics to a timestamped directory")
+	v.cmd.PersistentFlags().Bool("dry-run",
+		false,
+		"show the expanded agent command, but do not execute it")
+	v.cmd.PersistentFlags().String("tag",
+		"",
+		"experiment tag recorded in the run manifest")
+	v.cmd.PersistentFlags().String("note",
+		"",
+		"free form note recorded in the run manifest")
+	v.cmd.PersistentFlags().String("prompt",
+		"",
+		"override the default prompt sent to the agent")
+	v.cmd.PersistentFlags().String("prompt-file",
+		"",
+		"load the prompt override from a file")
+	v.cmd.PersistentFlags().Bool("sandbox",
+		false,
+		"run the agent on a sandbox copy of the po files")
+	v.cmd.PersistentFlags().String("agent-output",
+		"full",
+		"agent output level: quiet, summary, or full")
+	v.cmd.PersistentFlags().String("run-id",
+		"",
+		"run ID to resume or associate artifacts of an earlier run")
+	v.cmd.PersistentFlags().Bool("tui",
+		false,
+		"show a live dashboard instead of interleaved output")
+	v.cmd.PersistentFlags().String("record",
+		"",
+		"record agent output and file changes to this directory")
+	v.cmd.PersistentFlags().String("replay",
+		"",
+		"replay a recorded agent session instead of running the agent")
+	viper.BindPFlag("agent-run--agent-cmd", v.cmd.PersistentFlags().Lookup("agent-cmd"))
+	viper.BindPFlag("agent-run--agent-output", v.cmd.PersistentFlags().Lookup("agent-output"))
+	viper.BindPFlag("agent-run--sandbox", v.cmd.PersistentFlags().Lookup("sandbox"))
+	viper.BindPFlag("agent-run--log-dir", v.cmd.PersistentFlags().Lookup("log-dir"))
+	viper.BindPFlag("agent-run--dry-run", v.cmd.PersistentFlags().Lookup("dry-run"))
+	viper.BindPFlag("agent-run--tag", v.cmd.PersistentFlags().Lookup("tag"))
+	viper.BindPFlag("agent-run--note", v.cmd.PersistentFlags().Lookup("note"))
+	viper.BindPFlag("agent-run--run-id", v.cmd.PersistentFlags().Lookup("run-id"))
+	viper.BindPFlag("agent-run--tui", v.cmd.PersistentFlags().Lookup("tui"))
+	viper.BindPFlag("agent-run--record", v.cmd.PersistentFlags().Lookup("record"))
+	viper.BindPFlag("agent-run--replay", v.cmd.PersistentFlags().Lookup("replay"))
+
+	v.cmd.AddCommand(v.translateCommand())
+	v.cmd.AddCommand(v.reviewCommand())
+	v.cmd.AddCommand(v.addReviewExampleCommand())
+	v.cmd.AddCommand(v.checkConfigCommand())
+
+	return v.cmd
+}
+
+// runOptions collects per-run settings from the persistent flags, and
+// passes them down as an explicit options struct.
+func (v *agentRunCommand) runOptions() util.AgentRunOptions {
+	return util.AgentRunOptions{
+		Prompt:     v.cmd.PersistentFlags().Lookup("prompt").Value.String(),
+		PromptFile: v.cmd.PersistentFlags().Lookup("prompt-file").Value.String(),
+	}
+}
+
+func (v *agentRunCommand) translateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "translate <XX.po>...",
+		Short:             "Run agent to translate XX.po file",
+		SilenceErrors:     true,
+		ValidArgsFunction: completePoFileArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdAgentRunTranslate(util.InterruptContext(), v.runOptions(), args...) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("batch",
+		false,
+		"extract untranslated and fuzzy entries for batch translation")
+	cmd.Flags().Bool("resume",
+		false,
+		"resume an interrupted batch translation from its checkpoint")
+	cmd.Flags().Bool("all-languages",
+		false,
+		"translate every po/XX.po file in the repository")
+	cmd.Flags().IntP("jobs",
+		"j",
+		1,
+		"number of languages to translate in parallel")
+	cmd.Flags().Int("max-attempts",
+		0,
+		"re-prompt the agent up to N times while entries remain untranslated")
+	cmd.Flags().Bool("tm",
+		false,
+		"pre-fill matches from the translation memory before the agent runs")
+	addCommitFlags(cmd, "agent-run-translate")
+	viper.BindPFlag("agent-run-translate--batch", cmd.Flags().Lookup("batch"))
+	viper.BindPFlag("agent-run-translate--tm", cmd.Flags().Lookup("tm"))
+	viper.BindPFlag("translate.max_attempts", cmd.Flags().Lookup("max-attempts"))
+	viper.BindPFlag("agent-run-translate--resume", cmd.Flags().Lookup("resume"))
+	viper.BindPFlag("agent-run-translate--all-languages", cmd.Flags().Lookup("all-languages"))
+	viper.BindPFlag("agent-run-translate--jobs", cmd.Flags().Lookup("jobs"))
+	return cmd
+}
+
+func (v *agentRunCommand) reviewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "review <XX.po>...",
+		Short:             "Run agent to review translations of XX.po file",
+		SilenceErrors:     true,
+		ValidArgsFunction: completePoFileArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdAgentRunReview(util.InterruptContext(), v.runOptions(), args...) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("apply",
+		false,
+		"interactively apply suggested fixes from the review")
+	cmd.Flags().Bool("annotate",
+		false,
+		"write review issues as \"#. review:\" comments to a copy of the po file")
+	cmd.Flags().Bool("resume",
+		false,
+		"resume a failed batched review, skipping completed batches")
+	cmd.Flags().Bool("reprompt",
+		false,
+		"re-prompt the agent once when its review output fails validation")
+	cmd.Flags().Bool("no-cache",
+		false,
+		"do not reuse cached review results of unchanged entries")
+	cmd.Flags().String("only-changed",
+		"",
+		"review only entries changed since this ref")
+	cmd.Flags().Lookup("only-changed").NoOptDefVal = "HEAD"
+	cmd.Flags().Int("batch-size",
+		0,
+		"number of entries sent to the agent in one review batch")
+	cmd.Flags().Int("batch-threshold",
+		0,
+		"number of entries above which a review is split into batches")
+	cmd.Flags().Int("max-parallel-batches",
+		0,
+		"number of review batches sent to the agent concurrently")
+	viper.BindPFlag("agent-run-review--apply", cmd.Flags().Lookup("apply"))
+	viper.BindPFlag("agent-run-review--annotate", cmd.Flags().Lookup("annotate"))
+	viper.BindPFlag("agent-run-review--resume", cmd.Flags().Lookup("resume"))
+	viper.BindPFlag("agent-run-review--reprompt", cmd.Flags().Lookup("reprompt"))
+	viper.BindPFlag("agent-run-review--only-changed", cmd.Flags().Lookup("only-changed"))
+	viper.BindPFlag("agent-run-review--no-cache", cmd.Flags().Lookup("no-cache"))
+	viper.BindPFlag("review.batch_size", cmd.Flags().Lookup("batch-size"))
+	viper.BindPFlag("review.batch_threshold", cmd.Flags().Lookup("batch-threshold"))
+	viper.BindPFlag("review.max_parallel_batches", cmd.Flags().Lookup("max-parallel-batches"))
+	return cmd
+}
+
+func (v *agentRunCommand) addReviewExampleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "add-review-example",
+		Short:         "Add a few-shot example for review prompts",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdAddReviewExample() {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("lang",
+		"",
+		"locale of the example")
+	cmd.Flags().String("msgid",
+		"",
+		"original string of the example")
+	cmd.Flags().String("msgstr",
+		"",
+		"bad translation of the example")
+	cmd.Flags().String("issue",
+		"",
+		"issue the agent is expected to report")
+	viper.BindPFlag("add-review-example--lang", cmd.Flags().Lookup("lang"))
+	viper.BindPFlag("add-review-example--msgid", cmd.Flags().Lookup("msgid"))
+	viper.BindPFlag("add-review-example--msgstr", cmd.Flags().Lookup("msgstr"))
+	viper.BindPFlag("add-review-example--issue", cmd.Flags().Lookup("issue"))
+	return cmd
+}
+
+func (v *agentRunCommand) checkConfigCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "check-config",
+		Short:         "Validate agent settings of the config file",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdCheckConfig() {
+				return errExecute
+			}
+			return nil
+		},
+	}
+}
+
+var agentRunCmd = agentRunCommand{}
+
+func init() {
+	rootCmd.AddCommand(agentRunCmd.Command())
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type agentTestCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *agentTestCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:               "agent-test <XX.po>...",
+		Short:             "Benchmark agents on scratch copies of po files",
+		SilenceErrors:     true,
+		ValidArgsFunction: completePoFileArgs,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			util.EnableGateSummary()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().String("agent-cmd",
+		"",
+		"agent command template, with placeholder {source}")
+	v.cmd.Flags().String("agents",
+		"",
+		"comma-separated named agents from the config file to compare")
+	v.cmd.Flags().String("html-report",
+		"",
+		"write a standalone HTML benchmark report to this file")
+	v.cmd.Flags().Bool("tui",
+		false,
+		"show a live dashboard instead of interleaved output")
+	viper.BindPFlag("agent-test--agent-cmd", v.cmd.Flags().Lookup("agent-cmd"))
+	viper.BindPFlag("agent-test--agents", v.cmd.Flags().Lookup("agents"))
+	v.cmd.RegisterFlagCompletionFunc("agents", completeAgentNames)
+	viper.BindPFlag("agent-test--html-report", v.cmd.Flags().Lookup("html-report"))
+	viper.BindPFlag("agent-test--tui", v.cmd.Flags().Lookup("tui"))
+
+	historyCmd := &cobra.Command{
+		Use:           "history",
+		Short:         "List past benchmark records",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdAgentTestHistory() {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	historyCmd.Flags().String("agent",
+		"",
+		"only show records of this agent")
+	historyCmd.Flags().String("action",
+		"",
+		"only show records of this action")
+	viper.BindPFlag("agent-test-history--agent", historyCmd.Flags().Lookup("agent"))
+	historyCmd.RegisterFlagCompletionFunc("agent", completeAgentNames)
+	viper.BindPFlag("agent-test-history--action", historyCmd.Flags().Lookup("action"))
+	v.cmd.AddCommand(historyCmd)
+
+	return v.cmd
+}
+
+func (v agentTestCommand) Execute(args []string) error {
+	if !util.CmdAgentTest(util.InterruptContext(), args...) {
+		return errExecute
+	}
+	return nil
+}
+
+var agentTestCmd = agentTestCommand{}
+
+func init() {
+	rootCmd.AddCommand(agentTestCmd.Command())
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type annotateCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *annotateCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "annotate <XX.po>",
+		Short:         "Insert or update reviewer notes in po file",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().String("msgid",
+		"",
+		"msgid of the entry to annotate")
+	v.cmd.Flags().String("note",
+		"",
+		"note to record for the entry")
+	viper.BindPFlag("annotate--msgid", v.cmd.Flags().Lookup("msgid"))
+	viper.BindPFlag("annotate--note", v.cmd.Flags().Lookup("note"))
+
+	return v.cmd
+}
+
+func (v annotateCommand) Execute(args []string) error {
+	if !util.CmdAnnotate(args[0]) {
+		return errExecute
+	}
+	return nil
+}
+
+var annotateCmd = annotateCommand{}
+
+func init() {
+	rootCmd.AddCommand(annotateCmd.Command())
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+)
+
+type botCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *botCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "bot",
+		Short:         "Respond to pull request labels in GitHub Actions",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+
+	return v.cmd
+}
+
+func (v botCommand) Execute(args []string) error {
+	if !util.CmdBot() {
+		return errExecute
+	}
+	return nil
+}
+
+var botCmd = botCommand{}
+
+func init() {
+	rootCmd.AddCommand(botCmd.Command())
+}
@@ -36,6 +36,14 @@ func (v *checkCommitsCommand) Command() *cobra.Command {
 	v.cmd.Flags().Bool("report-typos-as-errors",
 		false,
 		"consider typos as errors")
+	v.cmd.Flags().Bool("stats-consistency",
+		false,
+		"verify statistics in commit subject against the actual entry delta")
+	v.cmd.Flags().Bool("po-only",
+		false,
+		"verify commits touch only po/ and one language each")
+	viper.BindPFlag("check-commits--stats-consistency", v.cmd.Flags().Lookup("stats-consistency"))
+	viper.BindPFlag("check-commits--po-only", v.cmd.Flags().Lookup("po-only"))
 	viper.BindPFlag("check-commits--no-gpg", v.cmd.Flags().Lookup("no-gpg"))
 	viper.BindPFlag("check-commits--force", v.cmd.Flags().Lookup("force"))
 	viper.BindPFlag("check-commits--ignore-typos", v.cmd.Flags().Lookup("ignore-typos"))
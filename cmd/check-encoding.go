@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type checkEncodingCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *checkEncodingCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "check-encoding [<XX.po>...]",
+		Short:         "Audit po files for BOM, CRLF and charset problems",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().Bool("fix",
+		false,
+		"strip BOM and normalize line endings of working-tree po files")
+	v.cmd.Flags().String("range",
+		"",
+		"audit po files changed by the commits of this range")
+	viper.BindPFlag("check-encoding--fix", v.cmd.Flags().Lookup("fix"))
+	viper.BindPFlag("check-encoding--range", v.cmd.Flags().Lookup("range"))
+
+	return v.cmd
+}
+
+func (v checkEncodingCommand) Execute(args []string) error {
+	if !util.CmdCheckEncoding(args...) {
+		return errExecute
+	}
+	return nil
+}
+
+var checkEncodingCmd = checkEncodingCommand{}
+
+func init() {
+	rootCmd.AddCommand(checkEncodingCmd.Command())
+}
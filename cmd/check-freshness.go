@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type checkFreshnessCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *checkFreshnessCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "check-freshness",
+		Short:         "Check languages which fell behind po/git.pot",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().Int("threshold",
+		0,
+		"report languages behind by more than <threshold> entries")
+	v.cmd.Flags().String("remote",
+		"",
+		"fetch master branch from this remote before checking")
+	viper.BindPFlag("check-freshness--threshold", v.cmd.Flags().Lookup("threshold"))
+	viper.BindPFlag("check-freshness--remote", v.cmd.Flags().Lookup("remote"))
+
+	return v.cmd
+}
+
+func (v checkFreshnessCommand) Execute(args []string) error {
+	if !util.CmdCheckFreshness() {
+		return errExecute
+	}
+	return nil
+}
+
+var checkFreshnessCmd = checkFreshnessCommand{}
+
+func init() {
+	rootCmd.AddCommand(checkFreshnessCmd.Command())
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type checkHeaderCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *checkHeaderCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "check-header <XX.po>...",
+		Short:         "Check header metadata of XX.po file",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().Bool("fix",
+		false,
+		"fix Language, PO-Revision-Date and charset before checking")
+	viper.BindPFlag("check-header--fix", v.cmd.Flags().Lookup("fix"))
+
+	return v.cmd
+}
+
+func (v checkHeaderCommand) Execute(args []string) error {
+	if !util.CmdCheckHeader(args...) {
+		return errExecute
+	}
+	return nil
+}
+
+var checkHeaderCmd = checkHeaderCommand{}
+
+func init() {
+	rootCmd.AddCommand(checkHeaderCmd.Command())
+}
@@ -32,9 +32,45 @@ func (v *checkPoCommand) Command() *cobra.Command {
 	v.cmd.Flags().Bool("report-typos-as-errors",
 		false,
 		"consider typos as errors")
+	v.cmd.Flags().Bool("spell",
+		false,
+		"spellcheck translations using hunspell or aspell")
+	v.cmd.Flags().Bool("glossary",
+		false,
+		"check translations against glossary in "+util.GlossaryDir)
+	v.cmd.Flags().Bool("no-fuzzy",
+		false,
+		"report fuzzy entries as errors")
+	v.cmd.Flags().Bool("no-obsolete",
+		false,
+		"report obsolete (#~) entries as errors")
+	v.cmd.Flags().String("require-header-fields",
+		"",
+		"comma-separated header fields which must be present")
+	v.cmd.Flags().Bool("report-placeholders-as-errors",
+		false,
+		"consider lost usage placeholders as errors")
+	v.cmd.Flags().Bool("report-punctuation-as-errors",
+		false,
+		"consider mixed punctuation widths as errors")
+	v.cmd.Flags().Bool("ignore-alignment",
+		false,
+		"do not check alignment of help text blocks in .po file")
+	v.cmd.Flags().Bool("report-alignment-as-errors",
+		false,
+		"consider alignment issues as errors")
 	viper.BindPFlag("check-po--core", v.cmd.Flags().Lookup("core"))
 	viper.BindPFlag("check-po--ignore-typos", v.cmd.Flags().Lookup("ignore-typos"))
 	viper.BindPFlag("check-po--report-typos-as-errors", v.cmd.Flags().Lookup("report-typos-as-errors"))
+	viper.BindPFlag("check-po--spell", v.cmd.Flags().Lookup("spell"))
+	viper.BindPFlag("check-po--glossary", v.cmd.Flags().Lookup("glossary"))
+	viper.BindPFlag("check-po--no-fuzzy", v.cmd.Flags().Lookup("no-fuzzy"))
+	viper.BindPFlag("check-po--no-obsolete", v.cmd.Flags().Lookup("no-obsolete"))
+	viper.BindPFlag("check-po--require-header-fields", v.cmd.Flags().Lookup("require-header-fields"))
+	viper.BindPFlag("check-po--report-placeholders-as-errors", v.cmd.Flags().Lookup("report-placeholders-as-errors"))
+	viper.BindPFlag("check-po--report-punctuation-as-errors", v.cmd.Flags().Lookup("report-punctuation-as-errors"))
+	viper.BindPFlag("check-po--ignore-alignment", v.cmd.Flags().Lookup("ignore-alignment"))
+	viper.BindPFlag("check-po--report-alignment-as-errors", v.cmd.Flags().Lookup("report-alignment-as-errors"))
 
 	return v.cmd
 }
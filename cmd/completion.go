@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/git-l10n/git-po-helper/util"
+)
+
+type completionCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *completionCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "completion <bash|zsh|fish|powershell>",
+		Short:         "Generate shell completion script",
+		SilenceErrors: true,
+		ValidArgs:     []string{"bash", "zsh", "fish", "powershell"},
+		Args:          cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+
+	return v.cmd
+}
+
+func (v completionCommand) Execute(args []string) error {
+	root := rootCmd.Command()
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return root.GenZshCompletion(os.Stdout)
+	case "fish":
+		return root.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		return root.GenPowerShellCompletion(os.Stdout)
+	}
+	return newUserErrorF(`unknown shell "%s"`, args[0])
+}
+
+// completePoFileArgs completes "XX.po" file arguments from the po
+// directory of the repository.
+func completePoFileArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var locales []string
+	files, err := filepath.Glob(filepath.Join(util.PoDir, "*.po"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	for _, fileName := range files {
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		if strings.HasPrefix(locale, toComplete) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAgentNames completes the names of the agents defined in the
+// config files.
+func completeAgentNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	util.LoadConfig()
+	var names []string
+	for _, name := range util.AgentNames() {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+var completionCmd = completionCommand{}
+
+func init() {
+	rootCmd.AddCommand(completionCmd.Command())
+}
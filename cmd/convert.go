@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type convertCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *convertCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "convert <file>",
+		Short:         "Convert po files to and from other formats",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().String("to",
+		"",
+		"convert po file to this format (xliff, csv, tsv)")
+	v.cmd.Flags().String("from",
+		"",
+		"convert file of this format (xliff, csv, tsv) to po")
+	v.cmd.Flags().StringP("output",
+		"o",
+		"-",
+		"write result to this file, or stdout")
+	viper.BindPFlag("convert--to", v.cmd.Flags().Lookup("to"))
+	viper.BindPFlag("convert--from", v.cmd.Flags().Lookup("from"))
+	viper.BindPFlag("convert--output", v.cmd.Flags().Lookup("output"))
+
+	return v.cmd
+}
+
+func (v convertCommand) Execute(args []string) error {
+	if !util.CmdConvert(args[0]) {
+		return errExecute
+	}
+	return nil
+}
+
+var convertCmd = convertCommand{}
+
+func init() {
+	rootCmd.AddCommand(convertCmd.Command())
+}
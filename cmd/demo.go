@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+)
+
+type demoCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *demoCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "demo",
+		Short:         "Create demo workspace for git-po-helper",
+		SilenceErrors: true,
+	}
+	v.cmd.AddCommand(&cobra.Command{
+		Use:           "init [<dir>]",
+		Short:         "Create a throwaway repository with sample po files",
+		SilenceErrors: true,
+		Args:          cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workDir := "git-po-demo"
+			if len(args) > 0 {
+				workDir = args[0]
+			}
+			if !util.CmdDemoInit(workDir) {
+				return errExecute
+			}
+			return nil
+		},
+	})
+
+	return v.cmd
+}
+
+var demoCmd = demoCommand{}
+
+func init() {
+	rootCmd.AddCommand(demoCmd.Command())
+}
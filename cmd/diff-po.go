@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type diffPoCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *diffPoCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:   "diff-po <old.po> <new.po>",
+		Short: "Compare two po files entry-by-entry",
+		Long: "Compare two po files entry-by-entry, reporting added, removed,\n" +
+			"changed, and newly-fuzzy entries. A po file may also be given as\n" +
+			"a \"<commit>:<path>\" spec, such as \"HEAD~1:po/zh_CN.po\".",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().String("format",
+		"text",
+		"output format: text or json")
+	viper.BindPFlag("diff-po--format", v.cmd.Flags().Lookup("format"))
+
+	return v.cmd
+}
+
+func (v diffPoCommand) Execute(args []string) error {
+	if !util.CmdDiffPo(args[0], args[1]) {
+		return errExecute
+	}
+	return nil
+}
+
+var diffPoCmd = diffPoCommand{}
+
+func init() {
+	rootCmd.AddCommand(diffPoCmd.Command())
+}
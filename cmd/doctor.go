@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+)
+
+type doctorCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *doctorCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "doctor",
+		Short:         "Diagnose the environment of git-po-helper",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+
+	return v.cmd
+}
+
+func (v doctorCommand) Execute(args []string) error {
+	if !util.CmdDoctor() {
+		return errExecute
+	}
+	return nil
+}
+
+var doctorCmd = doctorCommand{}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd.Command())
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+)
+
+type gcCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *gcCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "gc",
+		Short:         "Prune old artifacts and caches of git-po-helper",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+
+	return v.cmd
+}
+
+func (v gcCommand) Execute(args []string) error {
+	if !util.CmdGc() {
+		return errExecute
+	}
+	return nil
+}
+
+var gcCmd = gcCommand{}
+
+func init() {
+	rootCmd.AddCommand(gcCmd.Command())
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type handoffCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *handoffCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "handoff",
+		Short:         "Build and import hand-off packages for language teams",
+		SilenceErrors: true,
+	}
+
+	buildCmd := &cobra.Command{
+		Use:           "build <XX>",
+		Short:         "Bundle po file, glossary, stats and report into a zip",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			locale := strings.TrimSuffix(filepath.Base(args[0]), ".po")
+			if !util.CmdHandoffBuild(locale) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	buildCmd.Flags().StringP("output",
+		"o",
+		"",
+		"write the handoff package to this file")
+	viper.BindPFlag("handoff-build--output", buildCmd.Flags().Lookup("output"))
+	v.cmd.AddCommand(buildCmd)
+
+	v.cmd.AddCommand(&cobra.Command{
+		Use:           "import <package.zip>",
+		Short:         "Validate and merge a returned handoff package",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdHandoffImport(args[0]) {
+				return errExecute
+			}
+			return nil
+		},
+	})
+
+	return v.cmd
+}
+
+var handoffCmd = handoffCommand{}
+
+func init() {
+	rootCmd.AddCommand(handoffCmd.Command())
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type msgSelectCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *msgSelectCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "msg-select <XX.po> [<range>...]",
+		Short:         "Select entries of a po file by index range or filter",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().Bool("untranslated",
+		false,
+		"only select untranslated entries")
+	v.cmd.Flags().Bool("fuzzy",
+		false,
+		"only select fuzzy entries")
+	v.cmd.Flags().String("grep",
+		"",
+		"only select entries whose msgid matches this regex")
+	v.cmd.Flags().StringP("output",
+		"o",
+		"",
+		"write selected entries to this file instead of stdout")
+	viper.BindPFlag("msg-select--untranslated", v.cmd.Flags().Lookup("untranslated"))
+	viper.BindPFlag("msg-select--fuzzy", v.cmd.Flags().Lookup("fuzzy"))
+	viper.BindPFlag("msg-select--grep", v.cmd.Flags().Lookup("grep"))
+	viper.BindPFlag("msg-select--output", v.cmd.Flags().Lookup("output"))
+
+	return v.cmd
+}
+
+func (v msgSelectCommand) Execute(args []string) error {
+	if !util.CmdMsgSelect(args...) {
+		return errExecute
+	}
+	return nil
+}
+
+var msgSelectCmd = msgSelectCommand{}
+
+func init() {
+	rootCmd.AddCommand(msgSelectCmd.Command())
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/git-l10n/git-po-helper/util"
+)
+
+type parseLogCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *parseLogCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "parse-log <file>...",
+		Short:         "Render saved agent output logs through the stream parsers",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().String("agent-output",
+		"full",
+		"agent output level: quiet, summary, or full")
+	v.cmd.Flags().Bool("tolerant",
+		false,
+		"downgrade stream problems of truncated files to warnings")
+	v.cmd.Flags().String("format",
+		"auto",
+		"force the parser of one agent kind: claude, codex, opencode, gemini, aider, or auto")
+	v.cmd.Flags().String("export",
+		"",
+		"write a transcript of the parsed logs to a \".md\" or \".html\" file")
+	v.cmd.Flags().String("stats",
+		"",
+		"aggregate statistics over a directory of saved logs")
+	viper.BindPFlag("parse-log--agent-output", v.cmd.Flags().Lookup("agent-output"))
+	viper.BindPFlag("parse-log--export", v.cmd.Flags().Lookup("export"))
+	viper.BindPFlag("parse-log--stats", v.cmd.Flags().Lookup("stats"))
+	viper.BindPFlag("parse-log--tolerant", v.cmd.Flags().Lookup("tolerant"))
+	viper.BindPFlag("parse-log--format", v.cmd.Flags().Lookup("format"))
+	v.cmd.RegisterFlagCompletionFunc("format",
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return []string{"auto", "claude", "codex", "opencode", "gemini", "aider"},
+				cobra.ShellCompDirectiveNoFileComp
+		})
+
+	return v.cmd
+}
+
+func (v parseLogCommand) Execute(args []string) error {
+	if !util.CmdParseLog(args...) {
+		return errExecute
+	}
+	return nil
+}
+
+var parseLogCmd = parseLogCommand{}
+
+func init() {
+	rootCmd.AddCommand(parseLogCmd.Command())
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type poJSONCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *poJSONCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "po-json",
+		Short:         "Convert between po files and a lossless JSON representation",
+		SilenceErrors: true,
+	}
+	v.cmd.PersistentFlags().StringP("output",
+		"o",
+		"",
+		"write output to this file instead of stdout")
+	viper.BindPFlag("po-json--output", v.cmd.PersistentFlags().Lookup("output"))
+
+	exportCmd := &cobra.Command{
+		Use:           "export <XX.po>",
+		Short:         "Export entries of a po file as JSON",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdPoJSONExport(args[0]) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	exportCmd.Flags().String("range",
+		"",
+		"only export entries in this index range, such as 101-200")
+	viper.BindPFlag("po-json-export--range", exportCmd.Flags().Lookup("range"))
+	v.cmd.AddCommand(exportCmd)
+
+	v.cmd.AddCommand(&cobra.Command{
+		Use:           "import <file.json>",
+		Short:         "Import translations of a JSON file back into a po file",
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdPoJSONImport(args[0]) {
+				return errExecute
+			}
+			return nil
+		},
+	})
+
+	return v.cmd
+}
+
+var poJSONCmd = poJSONCommand{}
+
+func init() {
+	rootCmd.AddCommand(poJSONCmd.Command())
+}
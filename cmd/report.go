@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+)
+
+type reportCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *reportCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "report",
+		Short:         "Generate reports from run results and po stats",
+		SilenceErrors: true,
+	}
+	v.cmd.AddCommand(v.prBodyCommand())
+	v.cmd.AddCommand(v.reviewCommand())
+	return v.cmd
+}
+
+func (v *reportCommand) reviewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "review [<XX.po>...]",
+		Short:         "Show per-language review score trends from the history",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdReportReview(args...) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+}
+
+func (v *reportCommand) prBodyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "pr-body [<range>]",
+		Short:         "Write a Markdown pull request description for l10n changes",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdReportPrBody(args...) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+}
+
+var reportCmd = reportCommand{}
+
+func init() {
+	rootCmd.AddCommand(reportCmd.Command())
+}
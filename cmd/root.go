@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/git-l10n/git-po-helper/util"
 	"github.com/git-l10n/git-po-helper/version"
@@ -89,6 +90,14 @@ func (v *rootCommand) initLog() {
 		f.ForceColors = true
 	}
 	log.SetFormatter(f)
+	switch util.FlagOutputStyle() {
+	case "", "text":
+	case "github":
+		log.AddHook(&util.GitHubAnnotationHook{})
+	default:
+		log.Fatalf(`unknown output style "%s", expect "text" or "github"`,
+			util.FlagOutputStyle())
+	}
 	verbose := util.FlagVerbose()
 	quiet := util.FlagQuiet()
 	if verbose == 1 {
@@ -103,6 +112,42 @@ func (v *rootCommand) initLog() {
 }
 
 func (v *rootCommand) preCheck() {
+	// "demo init" creates its own repository, and must be able to
+	// run outside of any git workspace.
+	if len(os.Args) > 1 && os.Args[1] == "demo" {
+		return
+	}
+	// "completion" generates shell scripts, and the hidden
+	// "__complete" command resolves dynamic completions; neither may
+	// depend on running inside a repository.
+	if len(os.Args) > 1 && (os.Args[1] == "completion" ||
+		strings.HasPrefix(os.Args[1], cobra.ShellCompRequestCmd)) {
+		return
+	}
+	// "version" and "doctor" are often run to fill in bug reports
+	// about a broken environment, so they must not depend on that
+	// environment. "parse-log" renders saved log files, which may be
+	// inspected far away from the repository they were recorded in.
+	if len(os.Args) > 1 && (os.Args[1] == "version" ||
+		os.Args[1] == "doctor" || os.Args[1] == "parse-log") {
+		if cwd, err := os.Getwd(); err == nil {
+			util.GitRootDir = cwd
+		}
+		util.LoadConfig()
+		return
+	}
+	if util.FlagNoRepo() {
+		// Operate on plain po files, skipping any git based
+		// preparation steps.
+		if cwd, err := os.Getwd(); err == nil {
+			util.GitRootDir = cwd
+		}
+		util.LoadConfig()
+		if err := util.CheckPrereq(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	err := util.OpenRepository(".")
 	if err != nil {
 		log.Fatalf("fail to open repo: %s", err)
@@ -110,6 +155,7 @@ func (v *rootCommand) preCheck() {
 	if err = os.Chdir(util.GitRootDir); err != nil {
 		log.Fatalf("fail to chdir: %s", err)
 	}
+	util.LoadConfig()
 	err = util.CheckPrereq()
 	if err != nil {
 		log.Fatal(err)
@@ -152,7 +198,23 @@ func (v *rootCommand) Command() *cobra.Command {
 	v.cmd.PersistentFlags().Bool("no-gettext-back-compatible",
 		false,
 		"no check using gettext 0.14 for back compatible")
+	v.cmd.PersistentFlags().Int("progress-fd",
+		0,
+		"fd to write machine-parseable progress events")
+	v.cmd.PersistentFlags().Bool("strict",
+		false,
+		"treat warnings as errors")
+	v.cmd.PersistentFlags().String("config",
+		"",
+		"use this config file instead of the default ones")
+	v.cmd.PersistentFlags().String("output-style",
+		"text",
+		"output style: text, or github for workflow annotations")
+	v.cmd.PersistentFlags().Bool("no-repo",
+		false,
+		"operate on po files outside of a git repository")
 	v.cmd.PersistentFlags().MarkHidden("dryrun")
+	v.cmd.PersistentFlags().MarkHidden("progress-fd")
 	v.cmd.PersistentFlags().MarkHidden("no-gettext-back-compatible")
 	v.cmd.PersistentFlags().MarkHidden("github-action")
 	v.cmd.PersistentFlags().MarkHidden("github-action-event")
@@ -175,6 +237,21 @@ func (v *rootCommand) Command() *cobra.Command {
 	viper.BindPFlag(
 		"github-action-event",
 		v.cmd.PersistentFlags().Lookup("github-action-event"))
+	viper.BindPFlag(
+		"progress-fd",
+		v.cmd.PersistentFlags().Lookup("progress-fd"))
+	viper.BindPFlag(
+		"strict",
+		v.cmd.PersistentFlags().Lookup("strict"))
+	viper.BindPFlag(
+		"config",
+		v.cmd.PersistentFlags().Lookup("config"))
+	viper.BindPFlag(
+		"output-style",
+		v.cmd.PersistentFlags().Lookup("output-style"))
+	viper.BindPFlag(
+		"no-repo",
+		v.cmd.PersistentFlags().Lookup("no-repo"))
 
 	return v.cmd
 }
@@ -195,6 +272,9 @@ func Execute() Response {
 	)
 
 	c, err := rootCmd.Command().ExecuteC()
+	if !util.ShowGateSummary() && err == nil {
+		err = errExecute
+	}
 	resp.Err = err
 	resp.Cmd = c
 	return resp
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type serveCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *serveCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Expose agent operations over HTTP",
+		Long: `Expose agent operations over HTTP.
+
+The API is unauthenticated, and the triggered runs execute the
+configured agent commands and rewrite po files in the repository.
+For this reason the server only listens on localhost by default,
+think twice before binding it to another address.`,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdServe(util.InterruptContext()) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	v.cmd.Flags().String("listen",
+		"127.0.0.1:8080",
+		"address to listen on (the API is unauthenticated)")
+	viper.BindPFlag("serve--listen", v.cmd.Flags().Lookup("listen"))
+
+	return v.cmd
+}
+
+var serveCmd = serveCommand{}
+
+func init() {
+	rootCmd.AddCommand(serveCmd.Command())
+}
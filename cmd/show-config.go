@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type showConfigCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *showConfigCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "show-config",
+		Short:         "Show the effective configuration",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().Bool("origin",
+		false,
+		"annotate each value with the config file it came from")
+	viper.BindPFlag("show-config--origin", v.cmd.Flags().Lookup("origin"))
+	return v.cmd
+}
+
+func (v showConfigCommand) Execute(args []string) error {
+	if !util.CmdShowConfig() {
+		return errExecute
+	}
+	return nil
+}
+
+var showConfigCmd = showConfigCommand{}
+
+func init() {
+	rootCmd.AddCommand(showConfigCmd.Command())
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type statsCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *statsCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "stats [<XX.po>...]",
+		Short:         "Show translation progress of po files",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return v.Execute(args)
+		},
+	}
+	v.cmd.Flags().String("format",
+		"table",
+		"output format: table, json, or markdown")
+	viper.BindPFlag("stats--format", v.cmd.Flags().Lookup("format"))
+
+	return v.cmd
+}
+
+func (v statsCommand) Execute(args []string) error {
+	if !util.CmdStats(args...) {
+		return errExecute
+	}
+	return nil
+}
+
+var statsCmd = statsCommand{}
+
+func init() {
+	rootCmd.AddCommand(statsCmd.Command())
+}
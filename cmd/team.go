@@ -38,9 +38,72 @@ func (v *teamCommand) Command() *cobra.Command {
 	viper.BindPFlag("team-leader", v.cmd.Flags().Lookup("leader"))
 	viper.BindPFlag("team-members", v.cmd.Flags().Lookup("members"))
 	viper.BindPFlag("team-check", v.cmd.Flags().Lookup("check"))
+
+	v.cmd.AddCommand(v.checkCommand())
+	v.cmd.AddCommand(v.addCommand())
+	v.cmd.AddCommand(v.formatCommand())
 	return v.cmd
 }
 
+func (v *teamCommand) checkCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "check",
+		Short:         "Validate syntax, duplicates and order of po/TEAMS",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdTeamCheck() {
+				return errExecute
+			}
+			return nil
+		},
+	}
+}
+
+func (v *teamCommand) addCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "add",
+		Short:         "Add a team to po/TEAMS",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdTeamAdd() {
+				return errExecute
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("language",
+		"",
+		`language of the team, e.g. "zh_CN (Simplified Chinese)"`)
+	cmd.Flags().String("repository",
+		"",
+		"repository of the team")
+	cmd.Flags().String("leader",
+		"",
+		`leader of the team, as "Name <email>"`)
+	cmd.Flags().StringSlice("member",
+		nil,
+		`member of the team, as "Name <email>", can be repeated`)
+	viper.BindPFlag("team-add--language", cmd.Flags().Lookup("language"))
+	viper.BindPFlag("team-add--repository", cmd.Flags().Lookup("repository"))
+	viper.BindPFlag("team-add--leader", cmd.Flags().Lookup("leader"))
+	viper.BindPFlag("team-add--member", cmd.Flags().Lookup("member"))
+	return cmd
+}
+
+func (v *teamCommand) formatCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "format",
+		Short:         "Rewrite po/TEAMS in canonical format",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdTeamFormat() {
+				return errExecute
+			}
+			return nil
+		},
+	}
+}
+
 func (v teamCommand) Execute(args []string) error {
 	if !util.ShowTeams(args...) {
 		return errExecute
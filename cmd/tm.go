@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/git-l10n/git-po-helper/util"
+	"github.com/spf13/cobra"
+)
+
+type tmCommand struct {
+	cmd *cobra.Command
+}
+
+func (v *tmCommand) Command() *cobra.Command {
+	if v.cmd != nil {
+		return v.cmd
+	}
+
+	v.cmd = &cobra.Command{
+		Use:           "tm",
+		Short:         "Manage the translation memory",
+		SilenceErrors: true,
+	}
+	v.cmd.AddCommand(v.buildCommand())
+	return v.cmd
+}
+
+func (v *tmCommand) buildCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:           "build [<XX.po>...]",
+		Short:         "Harvest translations from history into a compendium",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !util.CmdTMBuild(args...) {
+				return errExecute
+			}
+			return nil
+		},
+	}
+}
+
+var tmCmd = tmCommand{}
+
+func init() {
+	rootCmd.AddCommand(tmCmd.Command())
+}
@@ -3,10 +3,34 @@ package cmd
 import (
 	"github.com/git-l10n/git-po-helper/util"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// addCommitFlags defines the auto-commit options shared by the
+// commands which can commit their own results.
+func addCommitFlags(cmd *cobra.Command, prefix string) {
+	cmd.Flags().Bool("commit",
+		false,
+		"commit the changed po files after all validations pass")
+	cmd.Flags().BoolP("signoff",
+		"s",
+		false,
+		"add a Signed-off-by trailer to the auto-created commit")
+	cmd.Flags().Bool("gpg-sign",
+		false,
+		"GPG-sign the auto-created commit")
+	cmd.Flags().String("message-template",
+		"",
+		"commit message template, with placeholders {lang} and {count}")
+	viper.BindPFlag(prefix+"--commit", cmd.Flags().Lookup("commit"))
+	viper.BindPFlag(prefix+"--signoff", cmd.Flags().Lookup("signoff"))
+	viper.BindPFlag(prefix+"--gpg-sign", cmd.Flags().Lookup("gpg-sign"))
+	viper.BindPFlag(prefix+"--message-template", cmd.Flags().Lookup("message-template"))
+}
+
 var updateCmd = &cobra.Command{
 	Use:           "update <XX.po>...",
+	Aliases:       []string{"update-po"},
 	Short:         "Update XX.po file",
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -24,6 +48,21 @@ var updateCmd = &cobra.Command{
 	},
 }
 
+var updatePotCmd = &cobra.Command{
+	Use:           "update-pot",
+	Short:         "Regenerate po/git.pot from tracked sources using xgettext",
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !util.CmdUpdatePot() {
+			return errExecute
+		}
+		return nil
+	},
+}
+
 func init() {
+	addCommitFlags(updateCmd, "update")
+	addCommitFlags(updatePotCmd, "update-pot")
 	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(updatePotCmd)
 }
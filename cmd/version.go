@@ -1,7 +1,7 @@
 package cmd
 
 import (
-	"github.com/git-l10n/git-po-helper/version"
+	"github.com/git-l10n/git-po-helper/util"
 	"github.com/spf13/cobra"
 )
 
@@ -10,15 +10,18 @@ var versionCmd = &cobra.Command{
 	Use:           "version",
 	Short:         "Display the version of git-po-helper",
 	SilenceErrors: true,
-	Run:           func(cmd *cobra.Command, args []string) {},
-	Version:       version.Version,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		if !util.CmdVersion(verbose) {
+			return errExecute
+		}
+		return nil
+	},
 }
 
 func init() {
-	versionCmd.Flags().Bool("version",
-		true,
-		"show version")
-	versionCmd.SetVersionTemplate(`{{with .Parent.Name}}{{printf "%s " .}}{{end}}{{printf "version %s" .Version}}
-`)
+	versionCmd.Flags().Bool("verbose",
+		false,
+		"also show build info and versions of external tools")
 	rootCmd.AddCommand(versionCmd)
 }
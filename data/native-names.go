@@ -0,0 +1,40 @@
+package data
+
+// nativeNameMap is a small embedded subset of CLDR native language
+// names, covering locales which have po files in git.git.
+var nativeNameMap = map[string]string{
+	"bg":    "български",
+	"ca":    "català",
+	"cs":    "čeština",
+	"da":    "dansk",
+	"de":    "Deutsch",
+	"el":    "ελληνικά",
+	"es":    "español",
+	"fi":    "suomi",
+	"fr":    "français",
+	"hu":    "magyar",
+	"id":    "Indonesia",
+	"is":    "íslenska",
+	"it":    "italiano",
+	"ja":    "日本語",
+	"ko":    "한국어",
+	"nb":    "norsk bokmål",
+	"nl":    "Nederlands",
+	"pl":    "polski",
+	"pt_BR": "português (Brasil)",
+	"pt_PT": "português (Portugal)",
+	"ru":    "русский",
+	"sv":    "svenska",
+	"th":    "ไทย",
+	"tr":    "Türkçe",
+	"uk":    "українська",
+	"vi":    "Tiếng Việt",
+	"zh_CN": "中文（简体）",
+	"zh_TW": "中文（繁體）",
+}
+
+// GetNativeLanguageName returns the native name of a locale, or an
+// empty string if the locale is not in the embedded subset.
+func GetNativeLanguageName(locale string) string {
+	return nativeNameMap[locale]
+}
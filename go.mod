@@ -9,4 +9,6 @@ require (
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/cobra v1.1.3
 	github.com/spf13/viper v1.7.1
+	golang.org/x/text v0.3.2
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,139 @@
+// Package agent provides a stable Go API for driving translation and
+// review agents on the po files of a Git worktree, so other tools can
+// orchestrate translations programmatically without going through the
+// git-po-helper command line.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/git-l10n/git-po-helper/util"
+)
+
+// Config holds the explicit settings of a Runner. Zero values fall
+// back to the defaults of the command line interface.
+type Config struct {
+	// Name of the agent, for display purpose. Defaults to the first
+	// word of Cmd.
+	Name string
+
+	// Cmd is the agent command template, with placeholder variables
+	// such as "{source}" and "{prompt}".
+	Cmd string
+
+	// Env holds extra environment variables for the agent.
+	Env map[string]string
+
+	// PromptVia defines how prompts are delivered to the agent:
+	// "arg", "stdin", or "file".
+	PromptVia string
+
+	// Prompt overrides the default prompt of translate and review
+	// runs.
+	Prompt string
+
+	// WorkDir is a directory inside the Git worktree to operate on.
+	// Defaults to the current directory.
+	WorkDir string
+
+	// Chdir changes the working directory of the process to the root
+	// of the worktree. Po files are resolved relative to the working
+	// directory, so leave this off only if the process already runs
+	// at the root of the worktree.
+	Chdir bool
+
+	// Batch extracts untranslated and fuzzy entries for batch
+	// translation, like option "--batch".
+	Batch bool
+
+	// MaxAttempts re-prompts the agent up to N times while entries
+	// remain untranslated, like option "--max-attempts".
+	MaxAttempts int
+
+	// LogOutput redirects the process-global logger for the run.
+	// Leave it nil to keep logging to stderr.
+	LogOutput io.Writer
+}
+
+// Runner drives one agent to translate or review po files.
+type Runner struct {
+	agent  *util.Agent
+	config Config
+	repo   *util.Repository
+}
+
+// Repository returns the explicit handle of the worktree the Runner
+// operates on.
+func (v *Runner) Repository() *util.Repository {
+	return v.repo
+}
+
+// NewRunner opens the Git worktree of config.WorkDir and creates a
+// Runner for the configured agent.
+//
+// WARNING: the underlying util functions still work on the implicit
+// global repository, so NewRunner mutates process-global state: it
+// points util.GitRootDir at the worktree, changes the working
+// directory of the process if config.Chdir is set, and redirects the
+// global logger if config.LogOutput is set. Runners for different
+// worktrees must not be used in the same process at the same time.
+func NewRunner(config Config) (*Runner, error) {
+	if config.Cmd == "" {
+		return nil, fmt.Errorf("config has no agent command")
+	}
+	if config.WorkDir == "" {
+		config.WorkDir = "."
+	}
+	repo, err := util.NewRepository(config.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+	util.GitRootDir = repo.WorkDir()
+	if config.Chdir {
+		if err := os.Chdir(repo.WorkDir()); err != nil {
+			return nil, err
+		}
+	}
+	if config.LogOutput != nil {
+		log.SetOutput(config.LogOutput)
+	}
+	agent := &util.Agent{
+		Name:      config.Name,
+		Cmd:       config.Cmd,
+		Prompt:    config.Prompt,
+		Env:       config.Env,
+		PromptVia: config.PromptVia,
+	}
+	if agent.Name == "" {
+		agent.Name = "agent"
+	}
+	return &Runner{agent: agent, config: config, repo: repo}, nil
+}
+
+// Translate runs the agent to translate "po/XX.po" of the given
+// locale. The context can be canceled to kill a running agent.
+func (v *Runner) Translate(ctx context.Context, locale string) error {
+	viper.Set("agent-run-translate--batch", v.config.Batch)
+	if v.config.MaxAttempts > 0 {
+		viper.Set("translate.max_attempts", v.config.MaxAttempts)
+	}
+	return util.RunAgentTranslate(ctx, v.agent, locale)
+}
+
+// Review runs the agent to review the translations of "po/XX.po" of
+// the given locale, and returns the structured review result.
+func (v *Runner) Review(ctx context.Context, locale string) (*util.ReviewJSONResult, error) {
+	report, err := util.RunAgentReview(ctx, v.agent,
+		filepath.Join(util.PoDir, locale+".po"))
+	if err != nil {
+		return nil, err
+	}
+	return util.ParseReviewJSON([]byte(report))
+}
@@ -0,0 +1,88 @@
+#!/bin/sh
+
+test_description="test git-po-helper check-po alignment and glossary checks"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir -p workdir/po/glossary &&
+	cat >workdir/po/zh_CN.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Project-Id-Version: Git\n"
+	"PO-Revision-Date: 2023-05-21 14:02+0800\n"
+	"Last-Translator: A U Thor <author@example.com>\n"
+	"Language-Team: Chinese\n"
+	"Language: zh_CN\n"
+	"MIME-Version: 1.0\n"
+	"Content-Type: text/plain; charset=UTF-8\n"
+	"Content-Transfer-Encoding: 8bit\n"
+	"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+	msgid ""
+	"usage:\n"
+	"    --force    do it\n"
+	msgstr ""
+	"用法：\n"
+	"  --force    执行\n"
+
+	msgid "cannot commit the change"
+	msgstr "无法记录变更"
+	EOF
+	cat >workdir/po/glossary/zh_CN.txt <<-\EOF &&
+	# Glossary for zh_CN.
+	commit	提交
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "alignment issues are warnings by default" '
+	(
+		cd workdir &&
+		$HELPER check-po zh_CN
+	) >out 2>&1 &&
+	grep "mismatched leading whitespace at line 2 of msgstr" out
+'
+
+test_expect_success "option --report-alignment-as-errors turns warnings into errors" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER check-po --report-alignment-as-errors zh_CN
+	) >out 2>&1 &&
+	grep "mismatched leading whitespace at line 2 of msgstr" out
+'
+
+test_expect_success "option --ignore-alignment suppresses alignment check" '
+	(
+		cd workdir &&
+		$HELPER check-po --ignore-alignment zh_CN
+	) >out 2>&1 &&
+	! grep "mismatched leading whitespace" out
+'
+
+test_expect_success "option --glossary reports missing mandated terms" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER check-po --ignore-alignment --glossary zh_CN
+	) >out 2>&1 &&
+	grep "glossary: term \"commit\" should be translated as \"提交\"" out
+'
+
+test_expect_success "glossary check passes after using the mandated term" '
+	(
+		cd workdir &&
+		sed -e "s/无法记录变更/无法提交变更/" <po/zh_CN.po >po/zh_CN.po.new &&
+		mv po/zh_CN.po.new po/zh_CN.po &&
+		$HELPER check-po --ignore-alignment --glossary zh_CN
+	) >out 2>&1 &&
+	! grep "glossary: term" out
+'
+
+test_done
@@ -0,0 +1,112 @@
+#!/bin/sh
+
+test_description="test git-po-helper stats"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir workdir/po &&
+	cat >workdir/po/zh_CN.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Project-Id-Version: Git\n"
+	"Language: zh_CN\n"
+	"Content-Type: text/plain; charset=UTF-8\n"
+	"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+	msgid "apple"
+	msgstr "苹果"
+
+	msgid "banana"
+	msgstr "香蕉"
+
+	#, fuzzy
+	msgid "cherry"
+	msgstr "樱桃"
+
+	msgid "date"
+	msgstr ""
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "stats in markdown format" '
+	(
+		cd workdir &&
+		$HELPER stats --format markdown zh_CN
+	) >out 2>&1 &&
+	grep "| 2 | 1 | 1 | 50.0% | 0 |" out
+'
+
+test_expect_success "stats in json format" '
+	(
+		cd workdir &&
+		$HELPER stats --format json zh_CN
+	) >out 2>&1 &&
+	grep "\"locale\": \"zh_CN\"" out &&
+	grep "\"translated\": 2" out &&
+	grep "\"fuzzy\": 1" out &&
+	grep "\"untranslated\": 1" out
+'
+
+test_expect_success "stats scans po directory without arguments" '
+	(
+		cd workdir &&
+		$HELPER stats --format json
+	) >out 2>&1 &&
+	grep "\"locale\": \"zh_CN\"" out
+'
+
+test_expect_success "stats reports pot delta" '
+	(
+		cd workdir &&
+		cat >po/git.pot <<-\EOF &&
+		msgid ""
+		msgstr ""
+		"Project-Id-Version: Git\n"
+		"Content-Type: text/plain; charset=UTF-8\n"
+
+		msgid "apple"
+		msgstr ""
+
+		msgid "banana"
+		msgstr ""
+
+		msgid "cherry"
+		msgstr ""
+
+		msgid "date"
+		msgstr ""
+
+		msgid "elderberry"
+		msgstr ""
+		EOF
+		$HELPER stats --format json zh_CN
+	) >out 2>&1 &&
+	grep "\"pot_delta\": 1" out
+'
+
+test_expect_success "stats fails for unknown locale" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER stats xx_XX
+	) >out 2>&1 &&
+	grep "level=error" out
+'
+
+test_expect_success "stats fails for unknown format" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER stats --format yaml zh_CN
+	) >out 2>&1 &&
+	grep "unknown stats format" out
+'
+
+test_done
@@ -0,0 +1,90 @@
+#!/bin/sh
+
+test_description="test git-po-helper msg-select"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir workdir/po &&
+	cat >workdir/po/zh_CN.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Project-Id-Version: Git\n"
+	"Language: zh_CN\n"
+	"Content-Type: text/plain; charset=UTF-8\n"
+	"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+	msgid "apple"
+	msgstr "苹果"
+
+	msgid "banana"
+	msgstr "香蕉"
+
+	#, fuzzy
+	msgid "cherry"
+	msgstr "樱桃"
+
+	msgid "date"
+	msgstr ""
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "select entries by index range" '
+	(
+		cd workdir &&
+		$HELPER msg-select po/zh_CN.po 1-2 -o selected.po
+	) &&
+	grep "msgid \"banana\"" workdir/selected.po &&
+	grep "msgid \"cherry\"" workdir/selected.po &&
+	! grep "msgid \"apple\"" workdir/selected.po &&
+	! grep "msgid \"date\"" workdir/selected.po
+'
+
+test_expect_success "selected po file keeps the header" '
+	grep "Project-Id-Version: Git" workdir/selected.po
+'
+
+test_expect_success "select untranslated entries" '
+	(
+		cd workdir &&
+		$HELPER msg-select --untranslated po/zh_CN.po -o untranslated.po
+	) &&
+	grep "msgid \"date\"" workdir/untranslated.po &&
+	! grep "msgid \"apple\"" workdir/untranslated.po
+'
+
+test_expect_success "select fuzzy entries" '
+	(
+		cd workdir &&
+		$HELPER msg-select --fuzzy po/zh_CN.po -o fuzzy.po
+	) &&
+	grep "msgid \"cherry\"" workdir/fuzzy.po &&
+	! grep "msgid \"banana\"" workdir/fuzzy.po
+'
+
+test_expect_success "select entries by grep pattern" '
+	(
+		cd workdir &&
+		$HELPER msg-select --grep "^ba" po/zh_CN.po -o grep.po
+	) &&
+	grep "msgid \"banana\"" workdir/grep.po &&
+	! grep "msgid \"cherry\"" workdir/grep.po
+'
+
+test_expect_success "fail for invalid range" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER msg-select po/zh_CN.po x-y
+	) >out 2>&1 &&
+	grep "invalid range" out
+'
+
+test_done
@@ -0,0 +1,91 @@
+#!/bin/sh
+
+test_description="test git-po-helper po-json"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir workdir/po &&
+	cat >workdir/po/zh_CN.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Project-Id-Version: Git\n"
+	"Language: zh_CN\n"
+	"Content-Type: text/plain; charset=UTF-8\n"
+	"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+	msgid "apple"
+	msgstr "苹果"
+
+	#, fuzzy
+	msgid "cherry"
+	msgstr "樱桃"
+
+	msgid "date"
+	msgstr ""
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "export po file as json" '
+	(
+		cd workdir &&
+		$HELPER po-json export po/zh_CN.po
+	) >out 2>&1 &&
+	grep "\"msgid\": \"apple\"" out &&
+	grep "\"msgstr\": \"苹果\"" out &&
+	grep "\"fuzzy\": true" out
+'
+
+test_expect_success "export a range of entries" '
+	(
+		cd workdir &&
+		$HELPER po-json export --range 1-2 po/zh_CN.po
+	) >out 2>&1 &&
+	! grep "\"msgid\": \"apple\"" out &&
+	grep "\"msgid\": \"cherry\"" out &&
+	grep "\"msgid\": \"date\"" out
+'
+
+test_expect_success "import translations from json" '
+	(
+		cd workdir &&
+		cat >import.json <<-\EOF &&
+		[
+		  {
+		    "index": 2,
+		    "msgid": "date",
+		    "msgstr": "枣"
+		  }
+		]
+		EOF
+		$HELPER po-json import import.json -o po/zh_CN.po
+	) >out 2>&1 &&
+	grep "imported 1 entries" out &&
+	grep "msgstr \"枣\"" workdir/po/zh_CN.po
+'
+
+test_expect_success "import fails for unknown msgid" '
+	(
+		cd workdir &&
+		cat >bad.json <<-\EOF &&
+		[
+		  {
+		    "msgid": "no such entry",
+		    "msgstr": "没有这个条目"
+		  }
+		]
+		EOF
+		test_must_fail $HELPER po-json import bad.json -o po/zh_CN.po
+	) >out 2>&1 &&
+	grep "no entry with msgid" out
+'
+
+test_done
@@ -0,0 +1,76 @@
+#!/bin/sh
+
+test_description="test git-po-helper convert between po and csv/tsv"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir workdir/po &&
+	cat >workdir/po/zh_CN.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Project-Id-Version: Git\n"
+	"Language: zh_CN\n"
+	"Content-Type: text/plain; charset=UTF-8\n"
+	"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+	msgid "apple"
+	msgstr "苹果"
+
+	#, fuzzy
+	msgid "cherry"
+	msgstr "樱桃"
+
+	msgid "date"
+	msgstr ""
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "convert po to csv" '
+	(
+		cd workdir &&
+		$HELPER convert --to csv po/zh_CN.po -o out.csv
+	) &&
+	grep "^msgctxt,msgid,msgstr,status,comment" workdir/out.csv &&
+	grep "^,apple,苹果,translated," workdir/out.csv &&
+	grep "^,cherry,樱桃,fuzzy," workdir/out.csv &&
+	grep "^,date,,untranslated," workdir/out.csv
+'
+
+test_expect_success "convert csv back to po" '
+	(
+		cd workdir &&
+		$HELPER convert --from csv out.csv -o back.po
+	) &&
+	grep "msgid \"apple\"" workdir/back.po &&
+	grep "msgstr \"苹果\"" workdir/back.po &&
+	grep "#, fuzzy" workdir/back.po
+'
+
+test_expect_success "convert po to tsv and back" '
+	(
+		cd workdir &&
+		$HELPER convert --to tsv po/zh_CN.po -o out.tsv &&
+		$HELPER convert --from tsv out.tsv -o back-tsv.po
+	) &&
+	grep "msgid \"cherry\"" workdir/back-tsv.po
+'
+
+test_expect_success "strict import rejects inconsistent status" '
+	(
+		cd workdir &&
+		sed -e "s/,fuzzy,/,reviewed,/" <out.csv >bad.csv &&
+		test_must_fail $HELPER convert --from csv bad.csv -o /dev/null
+	) >out 2>&1 &&
+	grep "invalid status" out
+'
+
+test_done
@@ -0,0 +1,69 @@
+#!/bin/sh
+
+test_description="test git-po-helper convert between po and xliff"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir workdir/po &&
+	cat >workdir/po/zh_CN.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Project-Id-Version: Git\n"
+	"Language: zh_CN\n"
+	"Content-Type: text/plain; charset=UTF-8\n"
+	"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+	# note for translators
+	msgid "apple"
+	msgstr "苹果"
+
+	#, fuzzy
+	msgid "cherry"
+	msgstr "樱桃"
+
+	msgid "date"
+	msgstr ""
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "convert po to xliff" '
+	(
+		cd workdir &&
+		$HELPER convert --to xliff po/zh_CN.po -o out.xlf
+	) &&
+	grep "target-language=\"zh_CN\"" workdir/out.xlf &&
+	grep "<source>apple</source>" workdir/out.xlf &&
+	grep "state=\"needs-review-translation\"" workdir/out.xlf &&
+	grep "state=\"new\"" workdir/out.xlf &&
+	grep "<note>note for translators</note>" workdir/out.xlf
+'
+
+test_expect_success "convert xliff back to po" '
+	(
+		cd workdir &&
+		$HELPER convert --from xliff out.xlf -o back.po
+	) &&
+	grep "msgid \"apple\"" workdir/back.po &&
+	grep "msgstr \"苹果\"" workdir/back.po &&
+	grep "#, fuzzy" workdir/back.po &&
+	grep "# note for translators" workdir/back.po
+'
+
+test_expect_success "convert fails for unknown format" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER convert --to sdlxliff po/zh_CN.po
+	) >out 2>&1 &&
+	grep "unknown format" out
+'
+
+test_done
@@ -0,0 +1,91 @@
+#!/bin/sh
+
+test_description="test git-po-helper diff-po"
+
+. ./lib/sharness.sh
+
+HELPER="git-po-helper --no-gettext-back-compatible"
+
+test_expect_success "setup" '
+	git init --quiet workdir &&
+	mkdir workdir/po &&
+	cat >workdir/old.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Content-Type: text/plain; charset=UTF-8\n"
+
+	msgid "apple"
+	msgstr "苹果"
+
+	msgid "banana"
+	msgstr "香蕉"
+
+	msgid "elderberry"
+	msgstr "接骨木莓"
+	EOF
+	cat >workdir/new.po <<-\EOF &&
+	msgid ""
+	msgstr ""
+	"Content-Type: text/plain; charset=UTF-8\n"
+
+	msgid "apple"
+	msgstr "苹果"
+
+	msgid "banana"
+	msgstr "大香蕉"
+
+	msgid "date"
+	msgstr "枣"
+	EOF
+	(
+		cd workdir &&
+		git add -A &&
+		git commit -q -m "setup"
+	)
+'
+
+test_expect_success "diff-po in text format" '
+	(
+		cd workdir &&
+		$HELPER diff-po old.po new.po
+	) >out 2>&1 &&
+	grep "^! banana" out &&
+	grep "^+ date" out &&
+	grep "^- elderberry" out &&
+	! grep "^. apple" out &&
+	grep "3 entries differ" out
+'
+
+test_expect_success "diff-po in json format" '
+	(
+		cd workdir &&
+		$HELPER diff-po --format json old.po new.po
+	) >out 2>&1 &&
+	grep "\"change\": \"changed\"" out &&
+	grep "\"change\": \"added\"" out &&
+	grep "\"change\": \"removed\"" out
+'
+
+test_expect_success "diff-po against a committed blob" '
+	(
+		cd workdir &&
+		cp new.po po/zh_CN.po &&
+		git add po/zh_CN.po &&
+		git commit -q -m "add po/zh_CN.po" &&
+		sed -e "s/大香蕉/超大香蕉/" <po/zh_CN.po >po/zh_CN.po.new &&
+		mv po/zh_CN.po.new po/zh_CN.po &&
+		$HELPER diff-po HEAD:po/zh_CN.po po/zh_CN.po
+	) >out 2>&1 &&
+	grep "^! banana" out &&
+	grep "1 entries differ" out
+'
+
+test_expect_success "diff-po fails for unknown format" '
+	(
+		cd workdir &&
+		test_must_fail $HELPER diff-po --format yaml old.po new.po
+	) >out 2>&1 &&
+	grep "unknown format" out
+'
+
+test_done
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// AgentKindAider marks an aider agent. Aider brings its own repo-aware
+// edit loop, so it only needs the right flags and a parser for its
+// plain text output.
+const AgentKindAider = "aider"
+
+// defaultAiderCmd is the command template of an "aider" kind agent,
+// unless the config overrides it. Auto commits are disabled, committing
+// is the job of the translator after the checks pass.
+const defaultAiderCmd = "aider --yes-always --no-auto-commits --message {prompt} {source}"
+
+// newAiderAgent creates an "aider" kind agent from the config section
+// of the given prefix, such as "agent" or "agents.<name>".
+func newAiderAgent(name, prefix string) (*Agent, error) {
+	cmd := viper.GetString(prefix + ".cmd")
+	if cmd == "" {
+		cmd = defaultAiderCmd
+	}
+	if err := checkAgentPlaceholders(cmd); err != nil {
+		return nil, err
+	}
+	return &Agent{
+		Name:      name,
+		Kind:      AgentKindAider,
+		Cmd:       cmd,
+		Env:       viper.GetStringMapString(prefix + ".env"),
+		PromptVia: viper.GetString(prefix + ".prompt_via"),
+	}, nil
+}
+
+// aiderStreamParser parses the plain text output of an aider agent:
+// edit confirmations, commit messages, and search/replace diff blocks.
+type aiderStreamParser struct {
+	inDiff bool
+}
+
+func (v *aiderStreamParser) Name() string {
+	return AgentKindAider
+}
+
+func (v *aiderStreamParser) ParseLine(line string) ([]AgentEvent, bool) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "<<<<<<< SEARCH"):
+		v.inDiff = true
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Name: "edit",
+			Text: trimmed,
+		}}, true
+	case strings.HasPrefix(trimmed, ">>>>>>> REPLACE"):
+		v.inDiff = false
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Text: trimmed,
+		}}, true
+	case v.inDiff:
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Text: line,
+		}}, true
+	case strings.HasPrefix(trimmed, "Applied edit to "):
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Name: "edit",
+			Text: strings.TrimPrefix(trimmed, "Applied edit to "),
+		}}, true
+	case strings.HasPrefix(trimmed, "Commit "):
+		return []AgentEvent{{
+			Type: AgentEventResult,
+			Text: trimmed,
+		}}, true
+	case strings.HasPrefix(trimmed, "Tokens:"), strings.HasPrefix(trimmed, "Cost:"),
+		strings.HasPrefix(trimmed, "Aider v"), strings.HasPrefix(trimmed, "Model:"):
+		return []AgentEvent{{Type: AgentEventSystem}}, true
+	}
+	return nil, false
+}
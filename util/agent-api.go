@@ -0,0 +1,171 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// AgentKindAPI marks an agent which calls an OpenAI-compatible chat
+// completion endpoint directly, without any external CLI tooling.
+const AgentKindAPI = "api"
+
+// apiAgentTimeout bounds one chat completion request.
+const apiAgentTimeout = 10 * time.Minute
+
+// defaultTranslateAPIPrompt is the instruction sent to an "api" kind
+// agent when translating a po file.
+const defaultTranslateAPIPrompt = `Translate the untranslated and fuzzy ` +
+	`entries of the following gettext po file to the language of its ` +
+	`"Language" header. Keep format specifiers, markup, and line breaks ` +
+	`of the original strings. Reply with the complete po file and ` +
+	`nothing else.`
+
+// newAPIAgent creates an "api" kind agent from the config section of
+// the given prefix, such as "agent" or "agents.<name>".
+func newAPIAgent(name, prefix string) (*Agent, error) {
+	agent := &Agent{
+		Name:      name,
+		Kind:      AgentKindAPI,
+		BaseURL:   strings.TrimSuffix(viper.GetString(prefix+".base_url"), "/"),
+		Model:     viper.GetString(prefix + ".model"),
+		APIKeyEnv: viper.GetString(prefix + ".api_key_env"),
+	}
+	if agent.BaseURL == "" || agent.Model == "" {
+		return nil, fmt.Errorf(
+			`api agent "%s" needs both "%s.base_url" and "%s.model" in the config file`,
+			name, prefix, prefix)
+	}
+	return agent, nil
+}
+
+// IsAPI indicates the agent calls a chat completion endpoint instead
+// of running an external command.
+func (v *Agent) IsAPI() bool {
+	return v.Kind == AgentKindAPI
+}
+
+// chatCompletion sends one prompt to the OpenAI-compatible endpoint of
+// the agent, and returns the text of the first completion choice.
+func (v *Agent) chatCompletion(ctx context.Context, prompt, content string) (string, error) {
+	request := struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}{
+		Model: v.Model,
+	}
+	request.Messages = append(request.Messages, struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}{
+		Role:    "user",
+		Content: prompt + "\n\n" + content,
+	})
+	buf, err := json.Marshal(&request)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiAgentTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST",
+		v.BaseURL+"/chat/completions", bytes.NewReader(buf))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.APIKeyEnv != "" {
+		key := os.Getenv(v.APIKeyEnv)
+		if key == "" {
+			return "", fmt.Errorf(
+				`api key environment variable "%s" of agent "%s" is not set`,
+				v.APIKeyEnv, v.Name)
+		}
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf(`fail to call agent "%s": %s`, v.Name, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(`agent "%s" returned status %s: %s`,
+			v.Name, resp.Status, truncateAgentLine(string(body)))
+	}
+	response := struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}{}
+	if err = json.Unmarshal(body, &response); err != nil {
+		return "", fmt.Errorf(`fail to parse response of agent "%s": %s`, v.Name, err)
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf(`agent "%s" returned no completion choices`, v.Name)
+	}
+	return stripCodeFence(response.Choices[0].Message.Content), nil
+}
+
+// stripCodeFence removes a surrounding markdown code fence which chat
+// models like to wrap around file content.
+func stripCodeFence(text string) string {
+	trimmed := strings.TrimSpace(text)
+	if !strings.HasPrefix(trimmed, "```") {
+		return text
+	}
+	lines := strings.Split(trimmed, "\n")
+	if len(lines) < 2 || strings.TrimSpace(lines[len(lines)-1]) != "```" {
+		return text
+	}
+	return strings.Join(lines[1:len(lines)-1], "\n") + "\n"
+}
+
+// runAPIAgentOnFile asks an "api" kind agent to translate a po file,
+// and writes the returned po file back. A non-empty followup is
+// appended to the prompt on repeated attempts.
+func runAPIAgentOnFile(ctx context.Context, agent *Agent, fileName, followup string) error {
+	buf, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	prompt, err := translatePrompt(agent)
+	if err != nil {
+		return err
+	}
+	if prompt == "" {
+		prompt = defaultTranslateAPIPrompt
+	}
+	locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+	if prompt, err = expandPromptTemplate(prompt, newPromptContext(locale, fileName)); err != nil {
+		return err
+	}
+	if note := lockedEntriesPromptNote(fileName); note != "" {
+		prompt += " " + note
+	}
+	if followup != "" {
+		prompt += "\n\n" + followup
+	}
+	result, err := agent.chatCompletion(ctx, prompt, string(buf))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, []byte(result), 0644)
+}
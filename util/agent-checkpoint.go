@@ -0,0 +1,67 @@
+package util
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// translateState records which entry batches of a translate session
+// were completed, so an interrupted session can be resumed.
+type translateState struct {
+	// Locale of the po file being translated.
+	Locale string `json:"locale"`
+
+	// BatchSize used to split entries when the session started.
+	BatchSize int `json:"batch_size"`
+
+	// Completed holds indexes (starting from 0) of finished batches.
+	Completed []int `json:"completed"`
+}
+
+func translateStateFile() string {
+	return filepath.Join(PoDir, ".translate-state.json")
+}
+
+// loadTranslateState reads the checkpoint file of a former translate
+// session for the given locale, and returns nil if there is none.
+func loadTranslateState(locale string) *translateState {
+	buf, err := ioutil.ReadFile(translateStateFile())
+	if err != nil {
+		return nil
+	}
+	state := translateState{}
+	if err = json.Unmarshal(buf, &state); err != nil {
+		return nil
+	}
+	if state.Locale != locale {
+		return nil
+	}
+	return &state
+}
+
+// saveTranslateState writes the checkpoint file of the running
+// translate session.
+func saveTranslateState(state *translateState) error {
+	buf, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(translateStateFile(), append(buf, '\n'), 0644)
+}
+
+// removeTranslateState removes the checkpoint file after a translate
+// session completes successfully.
+func removeTranslateState() {
+	os.Remove(translateStateFile())
+}
+
+func (v *translateState) isCompleted(batchNo int) bool {
+	for _, no := range v.Completed {
+		if no == batchNo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// BenchmarkRecord is one line of the benchmark history file, the
+// aggregated outcome of benchmarking one agent in one agent-test run.
+type BenchmarkRecord struct {
+	Time        string  `json:"time"`
+	Agent       string  `json:"agent"`
+	Action      string  `json:"action"`
+	Runs        int     `json:"runs"`
+	Failed      int     `json:"failed"`
+	AvgScore    int     `json:"avg_score"`
+	DurationSec float64 `json:"duration_sec"`
+	Commit      string  `json:"commit,omitempty"`
+}
+
+// benchmarkHistoryFile is the JSON-lines file which persists past
+// benchmark summaries, so regressions in prompts or models are visible
+// over time.
+func benchmarkHistoryFile() string {
+	return filepath.Join(HelperDir, "history", "benchmarks.jsonl")
+}
+
+// saveBenchmarkRecords appends benchmark records to the history file.
+func saveBenchmarkRecords(records []BenchmarkRecord) error {
+	fileName := benchmarkHistoryFile()
+	if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, record := range records {
+		buf, err := json.Marshal(&record)
+		if err != nil {
+			return err
+		}
+		if _, err = f.Write(append(buf, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadBenchmarkRecords reads all benchmark records from the history
+// file, and returns an empty list if there is none.
+func loadBenchmarkRecords() ([]BenchmarkRecord, error) {
+	var records []BenchmarkRecord
+
+	f, err := os.Open(benchmarkHistoryFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		record := BenchmarkRecord{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("invalid benchmark record at %s:%d: %s",
+				benchmarkHistoryFile(), lineNo, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// poCommitID returns the commit which last touched "po/", to correlate
+// benchmark records with the state of the po files.
+func poCommitID() string {
+	cmd := exec.Command("git", "log", "-1", "--format=%H", "--", PoDir)
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// recordBenchmarkResults aggregates per-file benchmark results into one
+// history record per agent, and appends them to the history file.
+func recordBenchmarkResults(action string, results []AgentTestResult) {
+	var (
+		records []BenchmarkRecord
+		commit  = poCommitID()
+		now     = time.Now().Format(time.RFC3339)
+		byAgent = make(map[string]*BenchmarkRecord)
+		agents  []string
+	)
+	for _, result := range results {
+		record, ok := byAgent[result.Agent]
+		if !ok {
+			record = &BenchmarkRecord{
+				Time:   now,
+				Agent:  result.Agent,
+				Action: action,
+				Commit: commit,
+			}
+			byAgent[result.Agent] = record
+			agents = append(agents, result.Agent)
+		}
+		record.Runs++
+		if result.Error != "" {
+			record.Failed++
+		}
+		record.AvgScore += result.Score
+		record.DurationSec += result.DurationSec
+	}
+	for _, agent := range agents {
+		record := byAgent[agent]
+		record.AvgScore /= record.Runs
+		records = append(records, *record)
+	}
+	if err := saveBenchmarkRecords(records); err != nil {
+		log.Errorf("fail to save benchmark history: %s", err)
+	}
+}
+
+// CmdAgentTestHistory implements agent-test history sub command, which
+// lists past benchmark records, optionally filtered by agent or action.
+func CmdAgentTestHistory() bool {
+	records, err := loadBenchmarkRecords()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	agent := FlagAgentHistoryAgent()
+	action := FlagAgentHistoryAction()
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Time\tAgent\tAction\tRuns\tFailed\tAvg Score\tDuration\tCommit")
+	shown := 0
+	for _, record := range records {
+		if agent != "" && record.Agent != agent {
+			continue
+		}
+		if action != "" && record.Action != action {
+			continue
+		}
+		commit := record.Commit
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%.1fs\t%s\n",
+			record.Time, record.Agent, record.Action,
+			record.Runs, record.Failed, record.AvgScore,
+			record.DurationSec, commit)
+		shown++
+	}
+	w.Flush()
+	if shown == 0 {
+		log.Infof("no matching benchmark records in \"%s\"", benchmarkHistoryFile())
+	}
+	return true
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package util
+
+import (
+	"os/exec"
+)
+
+// setAgentProcessGroup is a no-op on Windows, there is no process
+// group to set up.
+func setAgentProcessGroup(cmd *exec.Cmd) {
+}
+
+// killAgentProcessGroup kills the agent command on Windows, child
+// processes of the agent are not tracked.
+func killAgentProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	cmd.Process.Kill()
+}
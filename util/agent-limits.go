@@ -0,0 +1,112 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// Limits protecting the stream parsers from a runaway agent which
+// prints gigabytes of output: captured results are spilled to disk
+// once over the byte limit, and the display drops lines over the line
+// limit, leaving explicit truncation markers.
+
+// defaultMaxOutputBytes is the default of config variable
+// "agent.max_output_bytes", bounding captured agent results.
+const defaultMaxOutputBytes = 8 * 1024 * 1024
+
+// defaultMaxOutputLines is the default of config variable
+// "agent.max_output_lines", bounding displayed agent output lines.
+const defaultMaxOutputLines = 100000
+
+// maxPendingLineBytes bounds the reassembly buffer of a single line
+// without a newline, so one endless line cannot grow the buffer
+// unboundedly.
+const maxPendingLineBytes = 1024 * 1024
+
+// agentMaxOutputBytes returns config variable "agent.max_output_bytes".
+func agentMaxOutputBytes() int {
+	if max := viper.GetInt("agent.max_output_bytes"); max > 0 {
+		return max
+	}
+	return defaultMaxOutputBytes
+}
+
+// agentMaxOutputLines returns config variable "agent.max_output_lines".
+func agentMaxOutputLines() int {
+	if max := viper.GetInt("agent.max_output_lines"); max > 0 {
+		return max
+	}
+	return defaultMaxOutputLines
+}
+
+// limitedBuffer captures a result stream up to a byte limit in memory.
+// Once over the limit, the whole stream is spilled to a temporary file
+// instead, so an oversized result neither OOMs the process nor is
+// silently lost.
+type limitedBuffer struct {
+	max   int
+	buf   bytes.Buffer
+	spill *os.File
+	size  int64
+}
+
+// newLimitedBuffer creates a capture buffer bounded by config variable
+// "agent.max_output_bytes".
+func newLimitedBuffer() *limitedBuffer {
+	return &limitedBuffer{max: agentMaxOutputBytes()}
+}
+
+// Write implements the io.Writer interface.
+func (v *limitedBuffer) Write(p []byte) (int, error) {
+	v.size += int64(len(p))
+	if v.spill == nil && v.buf.Len()+len(p) <= v.max {
+		return v.buf.Write(p)
+	}
+	if v.spill == nil {
+		spill, err := ioutil.TempFile("", "git-po-helper-output-")
+		if err != nil {
+			return 0, err
+		}
+		v.spill = spill
+		if _, err := v.spill.Write(v.buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return v.spill.Write(p)
+}
+
+// Truncated indicates the stream went over the limit and was spilled
+// to disk.
+func (v *limitedBuffer) Truncated() bool {
+	return v.spill != nil
+}
+
+// SpillFile returns the file holding the complete oversized stream.
+func (v *limitedBuffer) SpillFile() string {
+	if v.spill == nil {
+		return ""
+	}
+	return v.spill.Name()
+}
+
+// String returns the captured stream, or a truncation marker naming
+// the spill file for an oversized stream.
+func (v *limitedBuffer) String() string {
+	if v.spill != nil {
+		return fmt.Sprintf("[%d bytes of output exceed the %d byte limit, spilled to %q]\n",
+			v.size, v.max, v.spill.Name())
+	}
+	return v.buf.String()
+}
+
+// Close closes the spill file, keeping it on disk for inspection.
+func (v *limitedBuffer) Close() error {
+	if v.spill != nil {
+		return v.spill.Close()
+	}
+	return nil
+}
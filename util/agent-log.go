@@ -0,0 +1,206 @@
+package util
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// runManifest records metadata of one agent-run invocation, written as
+// "manifest.json" in the run log directory. The tag and note fields
+// allow benchmark results to be grouped by experiment.
+type runManifest struct {
+	RunID     string   `json:"run_id"`
+	Tag       string   `json:"tag,omitempty"`
+	Note      string   `json:"note,omitempty"`
+	Args      []string `json:"args"`
+	StartedAt string   `json:"started_at"`
+}
+
+var (
+	agentLogDir     string
+	agentLogDirOnce sync.Once
+	agentLogSeq     int
+)
+
+// AgentLogDir returns the log directory of the current agent-run
+// invocation, named after its run ID and created under the directory
+// given by option "--log-dir" or config variable "agent_run.log_dir".
+// Running with "--run-id" of an earlier run reuses its directory.
+// Returns an empty string if logging is disabled.
+func AgentLogDir() string {
+	agentLogDirOnce.Do(func() {
+		baseDir := FlagAgentLogDir()
+		if baseDir == "" {
+			return
+		}
+		dir := filepath.Join(baseDir, RunID())
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Warnf("fail to create log dir: %s", err)
+			return
+		}
+		agentLogDir = dir
+		pruneOldRuns(baseDir)
+		if Exist(filepath.Join(dir, "manifest.json")) {
+			// Resuming an earlier run: keep its manifest, and
+			// continue the log file sequence after its files.
+			if entries, err := ioutil.ReadDir(dir); err == nil {
+				agentLogSeq = len(entries)
+			}
+			return
+		}
+		manifest := runManifest{
+			RunID:     RunID(),
+			Tag:       FlagAgentTag(),
+			Note:      FlagAgentNote(),
+			Args:      os.Args,
+			StartedAt: time.Now().Format(time.RFC3339),
+		}
+		buf, err := json.MarshalIndent(&manifest, "", "  ")
+		if err == nil {
+			err = ioutil.WriteFile(
+				filepath.Join(dir, "manifest.json"), append(buf, '\n'), 0644)
+		}
+		if err != nil {
+			log.Warnf("fail to write run manifest: %s", err)
+		}
+	})
+	return agentLogDir
+}
+
+// agentLogCompressThreshold is the size above which saved agent
+// streams are gzip compressed.
+const agentLogCompressThreshold = 64 * 1024
+
+// agentLogMaxFileSize is the size at which a streamed log file is
+// rotated to a numbered successor.
+const agentLogMaxFileSize = 32 * 1024 * 1024
+
+// rotatingLogFile is a streamed log file which rotates to numbered
+// successors once over the size limit, so one runaway agent cannot
+// fill the disk with a single giant file.
+type rotatingLogFile struct {
+	base string
+	seq  int
+	size int64
+	f    *os.File
+}
+
+// Write implements the io.Writer interface.
+func (v *rotatingLogFile) Write(p []byte) (int, error) {
+	if v.size+int64(len(p)) > agentLogMaxFileSize {
+		v.f.Close()
+		v.seq++
+		f, err := os.Create(fmt.Sprintf("%s.%d", v.base, v.seq))
+		if err != nil {
+			return 0, err
+		}
+		v.f = f
+		v.size = 0
+	}
+	n, err := v.f.Write(p)
+	v.size += int64(n)
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (v *rotatingLogFile) Close() error {
+	return v.f.Close()
+}
+
+// newAgentLogFile creates a log file with a sequence number prefix in
+// the log directory of the current invocation, and returns nil if
+// logging is disabled.
+func newAgentLogFile(name string) *rotatingLogFile {
+	dir := AgentLogDir()
+	if dir == "" {
+		return nil
+	}
+	agentLogSeq++
+	fileName := filepath.Join(dir, fmt.Sprintf("%03d-%s", agentLogSeq, name))
+	f, err := os.Create(fileName)
+	if err != nil {
+		log.Warnf("fail to create log file: %s", err)
+		return nil
+	}
+	return &rotatingLogFile{base: fileName, f: f}
+}
+
+// saveAgentLog writes diagnostics data to a file in the log directory
+// of the current invocation. Large agent streams are gzip compressed.
+func saveAgentLog(name string, data []byte) {
+	dir := AgentLogDir()
+	if dir == "" {
+		return
+	}
+	agentLogSeq++
+	fileName := filepath.Join(dir, fmt.Sprintf("%03d-%s", agentLogSeq, name))
+	var err error
+	if len(data) > agentLogCompressThreshold {
+		err = writeGzipFile(fileName+".gz", data)
+	} else {
+		err = ioutil.WriteFile(fileName, data, 0644)
+	}
+	if err != nil {
+		log.Warnf("fail to write log file: %s", err)
+	}
+}
+
+// writeGzipFile writes gzip compressed data to a file.
+func writeGzipFile(fileName string, data []byte) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(f)
+	if _, err = w.Write(data); err != nil {
+		w.Close()
+		f.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// pruneOldRuns removes the oldest run directories under a base
+// directory, keeping the newest N set by config variable
+// "output.keep_runs". Run directories are named after run IDs, which
+// sort chronologically. Zero keeps everything.
+func pruneOldRuns(baseDir string) {
+	keep := viper.GetInt("output.keep_runs")
+	if keep <= 0 {
+		return
+	}
+	entries, err := ioutil.ReadDir(baseDir)
+	if err != nil {
+		return
+	}
+	var runs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			runs = append(runs, entry.Name())
+		}
+	}
+	sort.Strings(runs)
+	for len(runs) > keep {
+		dir := filepath.Join(baseDir, runs[0])
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf("fail to prune old run: %s", err)
+			return
+		}
+		log.Debugf(`pruned old run "%s"`, dir)
+		runs = runs[1:]
+	}
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// AgentRunOptions carries per-run settings which are resolved by the
+// command layer and passed down explicitly, so this package does not
+// depend on the names of command line flags.
+type AgentRunOptions struct {
+	// Prompt overrides the default prompt sent to the agent.
+	Prompt string
+
+	// PromptFile loads the prompt override from a file, which is
+	// more convenient than the shell line for long prompts.
+	PromptFile string
+}
+
+// ResolvePrompt returns the prompt override of the options, loading it
+// from PromptFile if given. Returns an empty string if no override is
+// defined.
+func (v *AgentRunOptions) ResolvePrompt() (string, error) {
+	if v.PromptFile != "" {
+		buf, err := ioutil.ReadFile(v.PromptFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(buf)), nil
+	}
+	return v.Prompt, nil
+}
@@ -0,0 +1,546 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Output levels of the realtime agent output display.
+const (
+	AgentOutputQuiet   = "quiet"
+	AgentOutputSummary = "summary"
+	AgentOutputFull    = "full"
+)
+
+// Event types of the shared agent event model.
+const (
+	AgentEventSystem    = "system"
+	AgentEventAssistant = "assistant"
+	AgentEventTool      = "tool"
+	AgentEventResult    = "result"
+)
+
+// agentDisplayWidth is the maximum width of one displayed line of
+// agent output, longer lines are truncated.
+const agentDisplayWidth = 512
+
+// parserSniffWindow is the number of leading lines scanned for the
+// signature of a known agent kind, so blank lines or non-JSON banners
+// ahead of the stream cannot lock in the wrong parser.
+const parserSniffWindow = 20
+
+// AgentParseOptions controls how the streaming JSON output of an agent
+// is displayed: "quiet" shows only the final result, "summary" also
+// shows assistant messages, and "full" shows tool_use and tool_result
+// blocks as well. Format forces the parser of one agent kind instead
+// of auto-detection. Sink, if set, receives every recognized event
+// regardless of the display level, so transcripts and statistics can
+// be collected alongside the display.
+type AgentParseOptions struct {
+	Level  string
+	Format string
+	Sink   func(event AgentEvent)
+}
+
+// ShowAssistant indicates whether assistant messages are displayed.
+func (v *AgentParseOptions) ShowAssistant() bool {
+	return v.Level != AgentOutputQuiet
+}
+
+// ShowTools indicates whether tool_use and tool_result blocks are
+// displayed.
+func (v *AgentParseOptions) ShowTools() bool {
+	return v.Level == "" || v.Level == AgentOutputFull
+}
+
+// AgentEvent is the shared event model all agent stream parsers map
+// their output onto. Name holds the tool name of tool invocation
+// events, and is empty for tool results. Tokens and DurationMS are
+// session metrics, set on result events of agent kinds which report
+// them.
+type AgentEvent struct {
+	Type       string
+	Name       string
+	Text       string
+	Tokens     int
+	DurationMS int64
+}
+
+// AgentStreamParser parses one line of the streaming JSONL output of
+// one agent kind into shared events. Scanning, buffering, truncation
+// and icon rendering are shared by all kinds, a parser only maps the
+// JSON shape of its kind onto AgentEvent.
+type AgentStreamParser interface {
+	// Name returns the agent kind handled by the parser.
+	Name() string
+
+	// ParseLine maps one line onto shared events, and reports
+	// whether the line was recognized.
+	ParseLine(line string) ([]AgentEvent, bool)
+}
+
+// agentStreamParsers returns the parsers of all known agent kinds.
+// The aider parser matches plain text lines, so it comes last.
+func agentStreamParsers() []AgentStreamParser {
+	return []AgentStreamParser{
+		&claudeStreamParser{},
+		&codexStreamParser{},
+		&openCodeStreamParser{},
+		&geminiStreamParser{},
+		&aiderStreamParser{},
+	}
+}
+
+// claudeStreamParser parses the "--output-format stream-json" output
+// of a claude agent.
+type claudeStreamParser struct{}
+
+func (v *claudeStreamParser) Name() string {
+	return "claude"
+}
+
+func (v *claudeStreamParser) ParseLine(line string) ([]AgentEvent, bool) {
+	event := struct {
+		Type       string `json:"type"`
+		Result     string `json:"result"`
+		DurationMS int64  `json:"duration_ms"`
+		Usage      struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+		Message struct {
+			Content []struct {
+				Type    string          `json:"type"`
+				Text    string          `json:"text"`
+				Name    string          `json:"name"`
+				Input   json.RawMessage `json:"input"`
+				Content json.RawMessage `json:"content"`
+			} `json:"content"`
+		} `json:"message"`
+	}{}
+	if json.Unmarshal([]byte(line), &event) != nil || event.Type == "" {
+		return nil, false
+	}
+	var events []AgentEvent
+	switch event.Type {
+	case "system":
+		events = append(events, AgentEvent{Type: AgentEventSystem})
+	case "result":
+		events = append(events, AgentEvent{
+			Type:       AgentEventResult,
+			Text:       event.Result,
+			Tokens:     event.Usage.InputTokens + event.Usage.OutputTokens,
+			DurationMS: event.DurationMS,
+		})
+	case "assistant":
+		for _, block := range event.Message.Content {
+			switch block.Type {
+			case "text":
+				events = append(events, AgentEvent{
+					Type: AgentEventAssistant,
+					Text: block.Text,
+				})
+			case "tool_use":
+				events = append(events, AgentEvent{
+					Type: AgentEventTool,
+					Name: block.Name,
+					Text: string(block.Input),
+				})
+			}
+		}
+	case "user":
+		for _, block := range event.Message.Content {
+			if block.Type == "tool_result" {
+				events = append(events, AgentEvent{
+					Type: AgentEventTool,
+					Text: string(block.Content),
+				})
+			}
+		}
+	default:
+		return nil, false
+	}
+	return events, true
+}
+
+// codexStreamParser parses the "--json" output of a codex agent.
+type codexStreamParser struct{}
+
+func (v *codexStreamParser) Name() string {
+	return "codex"
+}
+
+func (v *codexStreamParser) ParseLine(line string) ([]AgentEvent, bool) {
+	event := struct {
+		Msg struct {
+			Type             string   `json:"type"`
+			Message          string   `json:"message"`
+			Command          []string `json:"command"`
+			LastAgentMessage string   `json:"last_agent_message"`
+		} `json:"msg"`
+	}{}
+	if json.Unmarshal([]byte(line), &event) != nil || event.Msg.Type == "" {
+		return nil, false
+	}
+	switch event.Msg.Type {
+	case "task_started", "session_configured":
+		return []AgentEvent{{Type: AgentEventSystem}}, true
+	case "agent_message":
+		return []AgentEvent{{
+			Type: AgentEventAssistant,
+			Text: event.Msg.Message,
+		}}, true
+	case "exec_command_begin":
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Name: "exec",
+			Text: strings.Join(event.Msg.Command, " "),
+		}}, true
+	case "exec_command_end":
+		return []AgentEvent{{Type: AgentEventTool}}, true
+	case "task_complete":
+		return []AgentEvent{{
+			Type: AgentEventResult,
+			Text: event.Msg.LastAgentMessage,
+		}}, true
+	}
+	return nil, false
+}
+
+// openCodeStreamParser parses the "--print-logs --format json" output
+// of an opencode agent.
+type openCodeStreamParser struct{}
+
+func (v *openCodeStreamParser) Name() string {
+	return "opencode"
+}
+
+func (v *openCodeStreamParser) ParseLine(line string) ([]AgentEvent, bool) {
+	event := struct {
+		Type string `json:"type"`
+		Part struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+			Tool string `json:"tool"`
+		} `json:"part"`
+		Text string `json:"text"`
+	}{}
+	if json.Unmarshal([]byte(line), &event) != nil || event.Type == "" {
+		return nil, false
+	}
+	switch event.Type {
+	case "step-start":
+		return []AgentEvent{{Type: AgentEventSystem}}, true
+	case "text":
+		return []AgentEvent{{
+			Type: AgentEventAssistant,
+			Text: event.Text,
+		}}, true
+	case "tool":
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Name: event.Part.Tool,
+			Text: event.Part.Text,
+		}}, true
+	case "step-finish":
+		return []AgentEvent{{
+			Type: AgentEventResult,
+			Text: event.Part.Text,
+		}}, true
+	}
+	return nil, false
+}
+
+// geminiStreamParser parses the "--output-format stream-json" output
+// of a gemini agent.
+type geminiStreamParser struct{}
+
+func (v *geminiStreamParser) Name() string {
+	return "gemini"
+}
+
+func (v *geminiStreamParser) ParseLine(line string) ([]AgentEvent, bool) {
+	event := struct {
+		Event string `json:"event"`
+		Text  string `json:"text"`
+		Name  string `json:"name"`
+		Args  string `json:"args"`
+	}{}
+	if json.Unmarshal([]byte(line), &event) != nil || event.Event == "" {
+		return nil, false
+	}
+	switch event.Event {
+	case "init":
+		return []AgentEvent{{Type: AgentEventSystem}}, true
+	case "content":
+		return []AgentEvent{{
+			Type: AgentEventAssistant,
+			Text: event.Text,
+		}}, true
+	case "tool_call":
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Name: event.Name,
+			Text: event.Args,
+		}}, true
+	case "tool_result":
+		return []AgentEvent{{
+			Type: AgentEventTool,
+			Text: event.Text,
+		}}, true
+	case "result":
+		return []AgentEvent{{
+			Type: AgentEventResult,
+			Text: event.Text,
+		}}, true
+	}
+	return nil, false
+}
+
+// agentOutputWriter reassembles the byte stream of an agent into
+// lines, and renders recognized stream events to the given writer
+// according to the display options. Lines which no parser recognizes
+// pass through unchanged.
+type agentOutputWriter struct {
+	out     io.Writer
+	opts    AgentParseOptions
+	buf     bytes.Buffer
+	parser  AgentStreamParser
+	parsers []AgentStreamParser
+	pending []string
+	sniffed bool
+	lines   int
+	dropped int
+	closed  bool
+	err     error
+}
+
+// newAgentOutputWriter creates a writer which renders the realtime
+// output of an agent to the given writer, so callers such as tests or
+// a TUI can redirect or capture the rendered output. An unknown forced
+// format falls back to auto-detection.
+func newAgentOutputWriter(out io.Writer, opts AgentParseOptions) *agentOutputWriter {
+	w := &agentOutputWriter{
+		out:     out,
+		opts:    opts,
+		parsers: agentStreamParsers(),
+	}
+	if opts.Format != "" && opts.Format != "auto" {
+		for _, parser := range w.parsers {
+			if parser.Name() == opts.Format {
+				w.parser = parser
+				w.sniffed = true
+				break
+			}
+		}
+	}
+	return w
+}
+
+// ParserName returns the name of the chosen stream parser, or an empty
+// string if no known agent kind was detected.
+func (v *agentOutputWriter) ParserName() string {
+	if v.parser == nil {
+		return ""
+	}
+	return v.parser.Name()
+}
+
+// Write implements the io.Writer interface.
+func (v *agentOutputWriter) Write(p []byte) (int, error) {
+	v.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(v.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(v.buf.Next(idx + 1))
+		v.parseLine(strings.TrimSuffix(line, "\n"))
+	}
+	// Bound the reassembly buffer, so one endless line without a
+	// newline cannot grow it without limit.
+	if v.buf.Len() > maxPendingLineBytes {
+		v.parseLine(v.buf.String())
+		v.buf.Reset()
+	}
+	return len(p), nil
+}
+
+// Close flushes the incomplete last line, and leaves an explicit
+// truncation marker if lines were dropped over the line limit. An
+// unterminated trailing JSON line, left behind when the agent is
+// killed mid-line, is reported instead of passing through as plain
+// text.
+func (v *agentOutputWriter) Close() error {
+	if v.closed {
+		return nil
+	}
+	v.closed = true
+	if v.buf.Len() > 0 {
+		line := v.buf.String()
+		v.buf.Reset()
+		if incompleteTrailingJSON(line) {
+			v.settle()
+			v.err = fmt.Errorf(
+				"stream ends with an incomplete JSON line of %d bytes, the agent was likely killed mid-line",
+				len(line))
+			fmt.Fprintf(v.out, "... %s\n", v.err)
+		} else {
+			v.parseLine(line)
+		}
+	}
+	v.settle()
+	if v.dropped > 0 {
+		fmt.Fprintf(v.out, "... %d more lines dropped over the %d line limit\n",
+			v.dropped, agentMaxOutputLines())
+	}
+	return nil
+}
+
+// Err returns the stream problem detected on close, such as an
+// incomplete trailing JSON line.
+func (v *agentOutputWriter) Err() error {
+	return v.err
+}
+
+// incompleteTrailingJSON reports whether the unterminated last line of
+// a stream looks like a truncated JSON document.
+func incompleteTrailingJSON(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return false
+	}
+	return !json.Valid([]byte(trimmed))
+}
+
+// parseLine maps one line of agent output onto shared events with the
+// parser of the detected agent kind, and renders them. Until a parser
+// is chosen, lines are held in a sniff window and probed against the
+// structured JSONL parsers first, so a plain text banner ahead of the
+// stream cannot lock in the aider parser by mistake.
+func (v *agentOutputWriter) parseLine(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	v.lines++
+	if v.lines > agentMaxOutputLines() {
+		v.dropped++
+		return
+	}
+	if v.parser != nil {
+		if events, ok := v.parser.ParseLine(line); ok {
+			v.render(events)
+			return
+		}
+	} else if !v.sniffed {
+		for _, parser := range v.parsers[:len(v.parsers)-1] {
+			events, ok := parser.ParseLine(line)
+			if !ok {
+				continue
+			}
+			v.choose(parser)
+			v.render(events)
+			return
+		}
+		v.pending = append(v.pending, line)
+		if len(v.pending) >= parserSniffWindow {
+			v.settle()
+		}
+		return
+	}
+	v.passThrough(line)
+}
+
+// choose locks in the detected stream parser, and replays the lines
+// held in the sniff window through it.
+func (v *agentOutputWriter) choose(parser AgentStreamParser) {
+	v.parser = parser
+	v.sniffed = true
+	log.Debugf(`detected "%s" agent output`, parser.Name())
+	pending := v.pending
+	v.pending = nil
+	for _, line := range pending {
+		if events, ok := parser.ParseLine(line); ok {
+			v.render(events)
+		} else {
+			v.passThrough(line)
+		}
+	}
+}
+
+// settle resolves the sniff window once no structured JSONL parser
+// matched it: the plain text aider parser is chosen if it recognizes
+// any held line, otherwise detection is given up and all further lines
+// pass through unchanged. A fresh parser probes the window, so the
+// stateful aider parser is not disturbed before the replay.
+func (v *agentOutputWriter) settle() {
+	if v.parser != nil || v.sniffed {
+		return
+	}
+	probe := &aiderStreamParser{}
+	for _, line := range v.pending {
+		if _, ok := probe.ParseLine(line); ok {
+			v.choose(v.parsers[len(v.parsers)-1])
+			return
+		}
+	}
+	v.sniffed = true
+	pending := v.pending
+	v.pending = nil
+	for _, line := range pending {
+		v.passThrough(line)
+	}
+}
+
+// passThrough shows one line which no parser recognizes.
+func (v *agentOutputWriter) passThrough(line string) {
+	if v.opts.Level != AgentOutputQuiet {
+		fmt.Fprintln(v.out, truncateAgentLine(line))
+	}
+}
+
+// render shows shared agent events according to the display options.
+func (v *agentOutputWriter) render(events []AgentEvent) {
+	for _, event := range events {
+		if v.opts.Sink != nil {
+			v.opts.Sink(event)
+		}
+		switch event.Type {
+		case AgentEventResult:
+			fmt.Fprintln(v.out, truncateAgentLine("✅ "+event.Text))
+		case AgentEventAssistant:
+			if v.opts.ShowAssistant() && event.Text != "" {
+				fmt.Fprintln(v.out, truncateAgentLine("🤖 "+event.Text))
+			}
+		case AgentEventTool:
+			if !v.opts.ShowTools() {
+				continue
+			}
+			if event.Name != "" {
+				fmt.Fprintln(v.out, truncateAgentLine(
+					fmt.Sprintf("🔧 %s %s", event.Name, event.Text)))
+			} else if event.Text != "" {
+				fmt.Fprintln(v.out, truncateAgentLine("💬 "+event.Text))
+			}
+		case AgentEventSystem:
+			// System events, such as the init event, carry no text
+			// to show.
+		}
+	}
+}
+
+// truncateAgentLine truncates one displayed line of agent output to
+// a sane width.
+func truncateAgentLine(line string) string {
+	line = strings.Replace(line, "\n", " ", -1)
+	runes := []rune(line)
+	if len(runes) <= agentDisplayWidth {
+		return line
+	}
+	return string(runes[:agentDisplayWidth]) + " ..."
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// gitStatusSnapshot returns the paths of the worktree which are
+// modified or untracked, with their porcelain status, so two snapshots
+// can be compared around an agent run.
+func gitStatusSnapshot() (map[string]string, error) {
+	return DefaultRepository().StatusSnapshot()
+}
+
+// StatusSnapshot returns the paths of the worktree which are modified
+// or untracked, with their porcelain status.
+func (v *Repository) StatusSnapshot() (map[string]string, error) {
+	cmd := v.GitCommand("status", "--porcelain")
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[string]string)
+	for _, line := range strings.Split(string(buf), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		snapshot[strings.TrimSpace(line[3:])] = line[:2]
+	}
+	return snapshot, nil
+}
+
+// allowedAgentFile tells whether an agent is expected to create or
+// modify the file: po files, the pot file, and the helper's own state
+// directory with logs, caches, and review outputs.
+func allowedAgentFile(path string) bool {
+	if path == filepath.Join(PoDir, GitPot) {
+		return true
+	}
+	if filepath.Dir(path) == PoDir && filepath.Ext(path) == ".po" {
+		return true
+	}
+	if strings.HasPrefix(path, HelperDir+"/") {
+		return true
+	}
+	return false
+}
+
+// agentPolicyViolations compares a git status snapshot taken before the
+// agent run with the state of the worktree afterwards, and returns the
+// files the agent created or modified outside the allowed list.
+func agentPolicyViolations(before map[string]string) []string {
+	after, err := gitStatusSnapshot()
+	if err != nil {
+		return nil
+	}
+	var violations []string
+	for path, status := range after {
+		if before[path] == status {
+			continue
+		}
+		if allowedAgentFile(path) {
+			continue
+		}
+		violations = append(violations, path)
+	}
+	sort.Strings(violations)
+	return violations
+}
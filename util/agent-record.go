@@ -0,0 +1,162 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Record-and-replay of real agent sessions. Option "--record <dir>"
+// captures the output stream, stderr, and file snapshots of every
+// agent invocation; option "--replay <dir>" feeds the recorded stream
+// back through the output parsers and applies the recorded file
+// changes, so parsing and validation logic can be regression-tested
+// without a real agent.
+
+// recordStep is one recorded agent invocation. The file name fields
+// are relative to the recording directory.
+type recordStep struct {
+	File   string `json:"file"`
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// recordManifest lists the recorded invocations in order, written as
+// "record.json" in the recording directory.
+type recordManifest struct {
+	Steps []recordStep `json:"steps"`
+}
+
+var (
+	recordMu    sync.Mutex
+	recordSteps []recordStep
+
+	replayOnce  sync.Once
+	replaySteps []recordStep
+	replayErr   error
+	replayNext  int
+)
+
+// recordAgentStep saves the captured output, stderr, and the before and
+// after snapshots of one agent invocation to the recording directory,
+// and updates the manifest. A unified diff of the snapshots is saved
+// beside them for human inspection.
+func recordAgentStep(fileName string, stdout, stderr, before []byte) {
+	dir := FlagAgentRecord()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Warnf("fail to create record dir: %s", err)
+		return
+	}
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	seq := len(recordSteps) + 1
+	step := recordStep{
+		File:   fileName,
+		Stdout: fmt.Sprintf("%03d-stdout.jsonl", seq),
+	}
+	save := func(name string, data []byte) string {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			log.Warnf("fail to write record file: %s", err)
+			return ""
+		}
+		return name
+	}
+	save(step.Stdout, stdout)
+	if len(stderr) > 0 {
+		step.Stderr = save(fmt.Sprintf("%03d-stderr.log", seq), stderr)
+	}
+	if before != nil {
+		step.Before = save(fmt.Sprintf("%03d-before.po", seq), before)
+	}
+	if after, err := ioutil.ReadFile(fileName); err == nil {
+		step.After = save(fmt.Sprintf("%03d-after.po", seq), after)
+	}
+	if step.Before != "" && step.After != "" {
+		// Best effort; "git diff --no-index" exits non-zero when the
+		// files differ.
+		cmd := exec.Command("git", "diff", "--no-index",
+			step.Before, step.After)
+		cmd.Dir = dir
+		if diff, _ := cmd.Output(); len(diff) > 0 {
+			save(fmt.Sprintf("%03d-diff.patch", seq), diff)
+		}
+	}
+	recordSteps = append(recordSteps, step)
+	buf, err := json.MarshalIndent(&recordManifest{Steps: recordSteps}, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, "record.json"), append(buf, '\n'), 0644)
+	}
+	if err != nil {
+		log.Warnf("fail to write record manifest: %s", err)
+	}
+}
+
+// loadReplaySteps reads the manifest of a recording directory once.
+func loadReplaySteps(dir string) ([]recordStep, error) {
+	replayOnce.Do(func() {
+		buf, err := ioutil.ReadFile(filepath.Join(dir, "record.json"))
+		if err != nil {
+			replayErr = fmt.Errorf("fail to read record manifest: %s", err)
+			return
+		}
+		manifest := recordManifest{}
+		if err := json.Unmarshal(buf, &manifest); err != nil {
+			replayErr = fmt.Errorf("fail to parse record manifest: %s", err)
+			return
+		}
+		replaySteps = manifest.Steps
+	})
+	return replaySteps, replayErr
+}
+
+// replayAgentStep replays the next recorded invocation: the recorded
+// output stream is fed through the agent output parsers, and the
+// recorded file changes are applied to the worktree.
+func replayAgentStep(dir, fileName string) error {
+	steps, err := loadReplaySteps(dir)
+	if err != nil {
+		return err
+	}
+	recordMu.Lock()
+	if replayNext >= len(steps) {
+		recordMu.Unlock()
+		return fmt.Errorf(
+			`recording "%s" has only %d steps, nothing left to replay`,
+			dir, len(steps))
+	}
+	step := steps[replayNext]
+	replayNext++
+	recordMu.Unlock()
+
+	log.Infof(`replaying recorded step %s of "%s" on "%s"`,
+		step.Stdout, dir, fileName)
+	buf, err := ioutil.ReadFile(filepath.Join(dir, step.Stdout))
+	if err != nil {
+		return fmt.Errorf("fail to read recorded stream: %s", err)
+	}
+	display := newAgentOutputWriter(agentConsole(os.Stdout),
+		AgentParseOptions{Level: FlagAgentOutput()})
+	if _, err := display.Write(buf); err != nil {
+		return err
+	}
+	display.Close()
+	saveAgentLog("output.log", buf)
+	if step.After != "" {
+		after, err := ioutil.ReadFile(filepath.Join(dir, step.After))
+		if err != nil {
+			return fmt.Errorf("fail to read recorded snapshot: %s", err)
+		}
+		if err := ioutil.WriteFile(fileName, after, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// followupMsgIDLimit caps how many msgids are listed in a follow-up
+// prompt, so the prompt does not explode for barely started locales.
+const followupMsgIDLimit = 50
+
+// AgentRunResult records the outcome of running an agent on one po
+// file, including how many attempts were needed and how many entries
+// were still untranslated or fuzzy afterwards.
+type AgentRunResult struct {
+	RunID     string `json:"run_id"`
+	Agent     string `json:"agent"`
+	File      string `json:"file"`
+	Attempts  int    `json:"attempts"`
+	Remaining int    `json:"remaining"`
+
+	// Violations lists files the agent created or modified outside
+	// the allowed list.
+	Violations []string `json:"violations,omitempty"`
+}
+
+// translateMaxAttempts returns the number of times the agent is
+// invoked on a po file before giving up on remaining untranslated or
+// fuzzy entries, set by config variable "translate.max_attempts".
+func translateMaxAttempts() int {
+	if attempts := viper.GetInt("translate.max_attempts"); attempts > 0 {
+		return attempts
+	}
+	return 1
+}
+
+// remainingMsgIDs returns the msgids of a po file which are still
+// untranslated or fuzzy.
+func remainingMsgIDs(fileName string) []string {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return nil
+	}
+	var msgIDs []string
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || entry.IsLocked() {
+			continue
+		}
+		if !entry.IsTranslated() || entry.IsFuzzy() {
+			msgIDs = append(msgIDs, entry.MsgID)
+		}
+	}
+	return msgIDs
+}
+
+// followupTranslatePrompt builds the prompt for another translation
+// attempt, listing the msgids which are still untranslated or fuzzy.
+func followupTranslatePrompt(msgIDs []string) string {
+	var buf strings.Builder
+	buf.WriteString("The following entries of the po file are still " +
+		"untranslated or fuzzy. Translate them and remove their fuzzy " +
+		"markers, and leave all other entries unchanged:\n")
+	for i, msgID := range msgIDs {
+		if i == followupMsgIDLimit {
+			fmt.Fprintf(&buf, "- ... and %d more entries\n", len(msgIDs)-i)
+			break
+		}
+		fmt.Fprintf(&buf, "- msgid %q\n", msgID)
+	}
+	return buf.String()
+}
+
+// saveRunResult writes the result of an agent run to the log directory
+// of the current invocation.
+func saveRunResult(result *AgentRunResult) {
+	buf, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return
+	}
+	saveAgentLog("result.json", append(buf, '\n'))
+}
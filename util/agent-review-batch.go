@@ -0,0 +1,189 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Defaults for batched reviews, which can be overridden by the
+// "review" section of the config file:
+//
+//	review:
+//	  batch_size: 50
+//	  batch_threshold: 100
+//	  max_parallel_batches: 1
+const (
+	defaultReviewBatchSize      = 50
+	defaultReviewBatchThreshold = 100
+)
+
+// reviewBatchSize returns the number of entries sent to the agent in
+// one review batch.
+func reviewBatchSize() int {
+	if size := viper.GetInt("review.batch_size"); size > 0 {
+		return size
+	}
+	return defaultReviewBatchSize
+}
+
+// reviewBatchThreshold returns the number of entries above which a
+// review is split into batches.
+func reviewBatchThreshold() int {
+	if threshold := viper.GetInt("review.batch_threshold"); threshold > 0 {
+		return threshold
+	}
+	return defaultReviewBatchThreshold
+}
+
+// reviewMaxParallelBatches returns the number of review batches sent to
+// the agent concurrently.
+func reviewMaxParallelBatches() int {
+	if jobs := viper.GetInt("review.max_parallel_batches"); jobs > 0 {
+		return jobs
+	}
+	return 1
+}
+
+// nrEntries returns the number of translatable entries of a po file,
+// not counting the header and obsolete entries.
+func nrEntries(fileName string) int {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range poFile.Entries {
+		if !entry.Obsolete && (entry.MsgID != "" || entry.hasCtxt) {
+			count++
+		}
+	}
+	return count
+}
+
+// runReviewBatched splits a po file into batches, reviews each batch
+// with the agent, and aggregates the issues of all batches in batch
+// order into one review report.
+func runReviewBatched(ctx context.Context, agent *Agent, poFile, locale string) (string, error) {
+	header, entries, err := splitPoEntries(poFile)
+	if err != nil {
+		return "", err
+	}
+	size := reviewBatchSize()
+	nrBatches := (len(entries) + size - 1) / size
+	log.Infof(`reviewing "%s" in %d batches of up to %d entries`,
+		poFile, nrBatches, size)
+
+	var progress *reviewProgress
+	if FlagReviewResume() {
+		progress = loadReviewProgress(locale, size, nrBatches)
+	}
+	if progress == nil {
+		progress = &reviewProgress{
+			Locale:    locale,
+			BatchSize: size,
+			NrBatches: nrBatches,
+			Results:   make(map[int]*ReviewJSONResult),
+		}
+	}
+
+	var (
+		results    = make([]*ReviewJSONResult, nrBatches)
+		errs       = make([]error, nrBatches)
+		tokens     = make(chan struct{}, reviewMaxParallelBatches())
+		progressMu sync.Mutex
+		wg         sync.WaitGroup
+	)
+	bar := newProgressBar(fmt.Sprintf("review %s", poFile), nrBatches)
+	defer bar.Done()
+	for batchNo := 0; batchNo < nrBatches; batchNo++ {
+		if result, ok := progress.Results[batchNo]; ok {
+			log.Infof(`batch %d/%d of "%s" already reviewed, skipped`,
+				batchNo+1, nrBatches, poFile)
+			results[batchNo] = result
+			bar.Add(1)
+			continue
+		}
+		wg.Add(1)
+		go func(batchNo int) {
+			defer wg.Done()
+			tokens <- struct{}{}
+			defer func() { <-tokens }()
+			if ctx.Err() != nil {
+				errs[batchNo] = ctx.Err()
+				return
+			}
+			begin := batchNo * size
+			end := begin + size
+			if end > len(entries) {
+				end = len(entries)
+			}
+			log.Infof(`reviewing batch %d/%d of "%s"`,
+				batchNo+1, nrBatches, poFile)
+			results[batchNo], errs[batchNo] = reviewBatch(
+				ctx, agent, locale, header, entries[begin:end])
+			if errs[batchNo] == nil {
+				bar.Add(1)
+			}
+			if errs[batchNo] == nil && !FlagAgentDryRun() {
+				progressMu.Lock()
+				progress.Results[batchNo] = results[batchNo]
+				if err := saveReviewProgress(progress); err != nil {
+					log.Warnf("fail to save review progress: %s", err)
+				}
+				progressMu.Unlock()
+			}
+		}(batchNo)
+	}
+	wg.Wait()
+
+	var sources []string
+	for batchNo := 0; batchNo < nrBatches; batchNo++ {
+		if errs[batchNo] != nil {
+			return "", fmt.Errorf(
+				"fail to review batch %d/%d (rerun with --resume to continue): %s",
+				batchNo+1, nrBatches, errs[batchNo])
+		}
+		sources = append(sources, fmt.Sprintf("batch %d/%d", batchNo+1, nrBatches))
+	}
+	aggregate := AggregateReviewJSON(sources, results)
+	removeReviewProgress()
+	buf, err := json.MarshalIndent(aggregate, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(buf) + "\n", nil
+}
+
+// reviewBatch writes a batch of entries to a temporary po file, runs
+// the agent on it, and parses the review report.
+func reviewBatch(ctx context.Context, agent *Agent, locale, header string, entries []string) (result *ReviewJSONResult, err error) {
+	endSpan := startSpan("agent/review-batch", "lang", locale, "entries", len(entries))
+	defer func() { endSpan("error", err != nil) }()
+	tmpFile, err := ioutil.TempFile("", "pofile")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpFile.Name())
+	buf := header + "\n" + strings.Join(entries, "\n")
+	if _, err = tmpFile.WriteString(buf); err != nil {
+		tmpFile.Close()
+		return nil, err
+	}
+	tmpFile.Close()
+	report, err := runAgentReviewFile(ctx, agent, tmpFile.Name(), locale)
+	if err != nil {
+		return nil, err
+	}
+	if FlagAgentDryRun() {
+		return &ReviewJSONResult{}, nil
+	}
+	return ParseReviewJSON([]byte(report))
+}
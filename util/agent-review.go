@@ -0,0 +1,376 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultReviewPrompt is the prompt sent to the agent when reviewing
+// translations of a po file.
+const defaultReviewPrompt = `Review the translations in the po file "{source}". ` +
+	`Check for mistranslations, broken format specifiers, and markup of ` +
+	`the original strings which are lost in translation. Report the ` +
+	`result as a JSON document of the form ` +
+	`{"issues": [{"msgid": ..., "msgstr": ..., "suggestion": ..., ` +
+	`"explanation": ..., "severity": "error|warning", ` +
+	`"category": "terminology|grammar|placeholder|punctuation|omission"}]}.`
+
+// CmdAgentRunReview implements agent-run review sub command.
+func CmdAgentRunReview(ctx context.Context, opts AgentRunOptions, args ...string) bool {
+	var ret = true
+
+	installRunIDHook()
+	startTUI()
+	defer stopTUI()
+	agent, err := NewAgentFromFlags()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if agent.Prompt, err = opts.ResolvePrompt(); err != nil {
+		log.Error(err)
+		return false
+	}
+	start := time.Now()
+	defer func() {
+		notifyAgentRun("review", agent, args, ret, time.Since(start))
+		flushTelemetry()
+	}()
+	if len(args) == 0 {
+		log.Errorf(`nothing to review, please provide "XX.po" files`)
+		return false
+	}
+	for _, fileName := range args {
+		locale, poFile := resolvePoFile(fileName)
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		setTUIStatus(locale, "reviewing")
+		var report string
+		switch {
+		case FlagReviewOnlyChanged() != "":
+			report, err = reviewChangedEntries(ctx, agent, poFile, locale,
+				FlagReviewOnlyChanged())
+		case !FlagReviewNoCache():
+			report, err = reviewWithCache(ctx, agent, poFile, locale)
+		default:
+			report, err = RunAgentReview(ctx, agent, poFile)
+		}
+		if err != nil {
+			setTUIStatus(locale, "failed: "+err.Error())
+			log.Errorf("fail to review \"%s\": %s", locale, err)
+			ret = false
+			continue
+		}
+		setTUIStatus(locale, "done")
+		if !FlagReviewApply() {
+			fmt.Fprint(agentConsole(os.Stdout), report)
+			if result, err := ParseReviewJSON([]byte(report)); err == nil {
+				addMTArtifactIssues(poFile, result)
+				addPlaceholderIssues(poFile, result)
+				addHotkeyIssues(poFile, result)
+				addCounter("review.issues", int64(len(result.Issues)))
+				score := reviewScoreOfPoFile(poFile, result)
+				ReportGate("review/"+locale, true, "score %d, %d issues", score, len(result.Issues))
+				log.Infof(`review score of "%s": %d`, poFile, score)
+				recordReviewResult(locale, score, result)
+				if breakdown := categoryBreakdown(result.Issues); breakdown != "" {
+					log.Infof(`issue categories of "%s": %s`, poFile, breakdown)
+				}
+				if FlagReviewAnnotate() {
+					if outFile, err := annotateReviewResult(poFile, result); err != nil {
+						log.Errorf(`fail to annotate "%s": %s`, poFile, err)
+						ret = false
+					} else {
+						log.Infof(`wrote annotated copy "%s"`, outFile)
+					}
+				}
+			} else if FlagStrict() {
+				log.Error(err)
+				ret = false
+			} else {
+				log.Warn(err)
+			}
+			continue
+		}
+		result, err := ParseReviewJSON([]byte(report))
+		if err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		addMTArtifactIssues(poFile, result)
+		addPlaceholderIssues(poFile, result)
+		addHotkeyIssues(poFile, result)
+		score := reviewScoreOfPoFile(poFile, result)
+		ReportGate("review/"+locale, true, "score %d, %d issues", score, len(result.Issues))
+		log.Infof(`review score of "%s": %d`, poFile, score)
+		recordReviewResult(locale, score, result)
+		if breakdown := categoryBreakdown(result.Issues); breakdown != "" {
+			log.Infof(`issue categories of "%s": %s`, poFile, breakdown)
+		}
+		if !applyReviewResult(poFile, result) {
+			ret = false
+		}
+	}
+	return ret
+}
+
+// addMTArtifactIssues appends issues found by the machine-translation
+// artifact rule pack to a review result, so heuristic findings show up
+// in the review JSON beside the issues reported by the agent.
+func addMTArtifactIssues(fileName string, result *ReviewJSONResult) {
+	issues, err := DetectMTArtifacts(fileName)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	for _, issue := range issues {
+		log.Warnf(`%s: %s`, fileName, issue.Explanation)
+	}
+	result.Issues = append(result.Issues, issues...)
+}
+
+// addPlaceholderIssues appends format specifier and placeholder
+// problems to a review result, so they show up in the review JSON
+// beside the issues reported by the agent.
+func addPlaceholderIssues(fileName string, result *ReviewJSONResult) {
+	issues, err := DetectPlaceholderIssues(fileName)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	for _, issue := range issues {
+		log.Warnf(`%s: %s`, fileName, issue.Explanation)
+	}
+	result.Issues = append(result.Issues, issues...)
+}
+
+// addHotkeyIssues appends accelerator and suspicious character
+// problems to a review result.
+func addHotkeyIssues(fileName string, result *ReviewJSONResult) {
+	issues, err := DetectHotkeyIssues(fileName)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+	for _, issue := range issues {
+		log.Warnf(`%s: %s`, fileName, issue.Explanation)
+	}
+	result.Issues = append(result.Issues, issues...)
+}
+
+// reviewScoreOfPoFile computes the review score of a po file with the
+// configured scoring strategy.
+func reviewScoreOfPoFile(fileName string, result *ReviewJSONResult) int {
+	nrEntries := 0
+	if poFile, err := LoadPoFile(fileName); err == nil {
+		for _, entry := range poFile.Entries {
+			if !entry.Obsolete {
+				nrEntries++
+			}
+		}
+	}
+	return CalculateReviewScore(nrEntries, result.Issues)
+}
+
+// applyReviewResult walks the review issues interactively, and lets
+// the user accept, skip, or edit the suggested fix of each issue.
+// Accepted fixes are written back to the po file and validated.
+func applyReviewResult(fileName string, result *ReviewJSONResult) bool {
+	if len(result.Issues) == 0 {
+		log.Infof(`no issues reported for "%s"`, fileName)
+		return true
+	}
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	entries := make(map[string]*PoEntry)
+	for _, entry := range poFile.Entries {
+		entries[entry.Key()] = entry
+	}
+	changed := 0
+	for i, issue := range result.Issues {
+		entry, ok := entries[poEntryKey(issue.MsgCtxt, issue.MsgID)]
+		if !ok {
+			log.Warnf(`issue %d: no entry with msgid "%s"`, i+1, issue.MsgID)
+			continue
+		}
+		fmt.Printf("\nissue %d/%d: %s\n", i+1, len(result.Issues), issue.Explanation)
+		fmt.Printf("     msgid: %s\n", issue.MsgID)
+		fmt.Printf("    msgstr: %s\n", entry.MsgStr)
+		if issue.Suggestion != "" {
+			fmt.Printf("suggestion: %s\n", issue.Suggestion)
+		}
+		answer := GetUserInput("apply suggestion? ([y]es/[s]kip/[e]dit): ", "s")
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			if issue.Suggestion == "" {
+				log.Warnf("issue %d has no suggestion, skipped", i+1)
+				continue
+			}
+			entry.MsgStr = issue.Suggestion
+			changed++
+		case "e", "edit":
+			text := GetUserInput("new msgstr: ", entry.MsgStr)
+			if text != entry.MsgStr {
+				entry.MsgStr = text
+				changed++
+			}
+		default:
+			continue
+		}
+	}
+	if changed == 0 {
+		return true
+	}
+	if err = poFile.Save(fileName); err != nil {
+		log.Error(err)
+		return false
+	}
+	if errs, ok := checkPoSyntax(fileName); !ok {
+		for _, err := range errs {
+			log.Error(err)
+		}
+		return false
+	}
+	log.Infof(`applied %d fixes to "%s"`, changed, fileName)
+	return true
+}
+
+// RunAgentReview runs the agent to review a po file, and returns the
+// review report from the agent. Large po files are split into batches,
+// which are reviewed separately and aggregated in order.
+func RunAgentReview(ctx context.Context, agent *Agent, poFile string) (string, error) {
+	if !Exist(poFile) {
+		return "", fmt.Errorf(`fail to review "%s", does not exist`, poFile)
+	}
+	locale := strings.TrimSuffix(filepath.Base(poFile), ".po")
+	if FlagAgentSandbox() {
+		// Review a sandbox copy, so the agent cannot touch the
+		// repository.
+		sandbox, err := ioutil.TempDir("", "git-po-helper-sandbox-")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(sandbox)
+		sandboxFile := filepath.Join(sandbox, PoDir, locale+".po")
+		if err := copyFile(sandboxFile, poFile); err != nil {
+			return "", err
+		}
+		poFile = sandboxFile
+	}
+	if nrEntries(poFile) > reviewBatchThreshold() {
+		return runReviewBatched(ctx, agent, poFile, locale)
+	}
+	return runAgentReviewFile(ctx, agent, poFile, locale)
+}
+
+// runAgentReviewFile runs the agent to review one po file, which may be
+// a temporary file holding a batch of entries of a "po/XX.po" file. If
+// the agent returns malformed review JSON, the agent is re-prompted
+// once with the validation errors appended.
+func runAgentReviewFile(ctx context.Context, agent *Agent, poFile, locale string) (string, error) {
+	prompt := agent.Prompt
+	if prompt == "" {
+		prompt = reviewPromptForLocale(locale)
+	}
+	prompt, err := expandPromptTemplate(prompt, newPromptContext(locale, poFile))
+	if err != nil {
+		return "", err
+	}
+	report, err := reviewOnce(ctx, agent, poFile, locale, prompt)
+	if err != nil || FlagAgentDryRun() {
+		return report, err
+	}
+	if _, err := ParseReviewJSON([]byte(report)); err != nil && FlagReviewReprompt() {
+		log.Warnf(`re-prompting agent "%s" once: %s`, agent.Name, err)
+		prompt = fmt.Sprintf("%s\n\nYour previous reply was rejected: %s. "+
+			"Reply with only the corrected JSON document.", prompt, err)
+		return reviewOnce(ctx, agent, poFile, locale, prompt)
+	}
+	return report, nil
+}
+
+// reviewOnce sends one review prompt for a po file to the agent, and
+// returns the raw report.
+func reviewOnce(ctx context.Context, agent *Agent, poFile, locale, prompt string) (string, error) {
+	if agent.IsSim() {
+		if FlagAgentDryRun() {
+			fmt.Printf("would replay sim agent \"%s\" (scenario %s) to review \"%s\"\n",
+				agent.Name, agent.Scenario, poFile)
+			return "", nil
+		}
+		log.Infof(`replaying sim agent "%s" (scenario %s) to review "%s"`,
+			agent.Name, agent.Scenario, poFile)
+		vars := agentVars(locale)
+		vars["source"] = poFile
+		report, err := runSimAgent(ctx, agent, vars)
+		if err != nil {
+			return "", err
+		}
+		saveAgentLog("review.log", []byte(report))
+		return report, nil
+	}
+	if agent.IsAPI() {
+		if FlagAgentDryRun() {
+			fmt.Printf("would call api agent \"%s\" (model %s) to review \"%s\"\n",
+				agent.Name, agent.Model, poFile)
+			return "", nil
+		}
+		log.Infof(`calling api agent "%s" (model %s) to review "%s"`,
+			agent.Name, agent.Model, poFile)
+		buf, err := ioutil.ReadFile(poFile)
+		if err != nil {
+			return "", err
+		}
+		report, err := agent.chatCompletion(ctx, prompt, string(buf))
+		if err != nil {
+			return "", err
+		}
+		saveAgentLog("review.log", []byte(report))
+		return report, nil
+	}
+	vars := agentVars(locale)
+	vars["source"] = poFile
+	cmd, cleanup, err := agent.CommandWithPrompt(vars, prompt)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	if FlagAgentDryRun() {
+		fmt.Printf("would run agent \"%s\":\n    %s\nwith review prompt:\n    %s\n",
+			agent.Name, strings.Join(cmd.Args, " "), prompt)
+		return "", nil
+	}
+	log.Infof(`running agent "%s" to review "%s"`, agent.Name, poFile)
+	saveAgentLog("cmd.txt", []byte(strings.Join(cmd.Args, " ")+"\n"))
+	report := newLimitedBuffer()
+	defer report.Close()
+	cmd.Stdout = report
+	cmd.Stderr = os.Stderr
+	if err := runAgentCmd(ctx, cmd); err != nil {
+		return "", fmt.Errorf(`fail to run agent "%s": %s`, agent.Name, err)
+	}
+	if report.Truncated() {
+		return "", fmt.Errorf(
+			`report of agent "%s" exceeds the %d byte limit, full output spilled to "%s"`,
+			agent.Name, agentMaxOutputBytes(), report.SpillFile())
+	}
+	saveAgentLog("review.log", []byte(report.String()))
+	return report.String(), nil
+}
@@ -0,0 +1,599 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// translateBatchSize is the number of entries sent to the agent in one
+// batch of a batch-mode translate session.
+const translateBatchSize = 100
+
+// translateResult is the outcome of translating one language, shown in
+// the consolidated summary table.
+type translateResult struct {
+	Locale   string
+	Duration time.Duration
+	Err      error
+}
+
+// CmdAgentRunTranslate implements agent-run translate sub command.
+func CmdAgentRunTranslate(ctx context.Context, opts AgentRunOptions, args ...string) bool {
+	var (
+		locales []string
+		ret     = true
+	)
+
+	installRunIDHook()
+	startTUI()
+	defer stopTUI()
+	agent, err := NewAgentFromFlags()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if agent.Prompt, err = opts.ResolvePrompt(); err != nil {
+		log.Error(err)
+		return false
+	}
+	start := time.Now()
+	defer func() {
+		notifyAgentRun("translate", agent, args, ret, time.Since(start))
+		flushTelemetry()
+	}()
+	if FlagAgentAllLanguages() {
+		args = append(args, listPoFiles()...)
+		sort.Strings(args)
+	}
+	if len(args) == 0 {
+		log.Errorf(`nothing to translate, please provide "XX.po" files`)
+		return false
+	}
+	var files []string
+	for _, fileName := range args {
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		locales = append(locales, locale)
+		files = append(files, fileName)
+	}
+
+	before := make(map[string]int)
+	if FlagAutoCommit() && !FlagNoRepo() {
+		for _, locale := range locales {
+			before[locale] = nrTranslated(filepath.Join(PoDir, locale+".po"))
+		}
+	}
+
+	jobs := FlagAgentJobs()
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs > 1 && FlagAgentResume() {
+		log.Warnf("option --resume is ignored when running %d parallel jobs", jobs)
+	}
+
+	var (
+		results = make([]translateResult, len(locales))
+		tokens  = make(chan struct{}, jobs)
+		wg      sync.WaitGroup
+	)
+	for i, locale := range locales {
+		wg.Add(1)
+		go func(i int, locale string) {
+			defer wg.Done()
+			tokens <- struct{}{}
+			defer func() { <-tokens }()
+			if ctx.Err() != nil {
+				results[i] = translateResult{Locale: locale, Err: ctx.Err()}
+				return
+			}
+			start := time.Now()
+			setTUIStatus(locale, "translating")
+			err := RunAgentTranslate(ctx, agent, files[i])
+			if err != nil {
+				setTUIStatus(locale, "failed: "+err.Error())
+			} else {
+				setTUIStatus(locale, "done")
+			}
+			results[i] = translateResult{
+				Locale:   locale,
+				Duration: time.Since(start).Round(time.Second),
+				Err:      err,
+			}
+		}(i, locale)
+	}
+	wg.Wait()
+	stopTUI()
+
+	if len(results) > 1 {
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "Run ID: %s\n", RunID())
+		fmt.Fprintln(w, "Language\tDuration\tResult")
+		for _, result := range results {
+			status := "ok"
+			if result.Err != nil {
+				status = result.Err.Error()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result.Locale, result.Duration, status)
+		}
+		w.Flush()
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			log.Errorf("fail to translate \"%s\": %s", result.Locale, result.Err)
+			ret = false
+			ReportGate("translate/"+result.Locale, false, "%s", result.Err)
+		} else {
+			ReportGate("translate/"+result.Locale, true, "finished in %s", result.Duration)
+		}
+	}
+	if FlagAutoCommit() && !FlagNoRepo() && !FlagAgentDryRun() {
+		for i, locale := range locales {
+			if results[i].Err != nil {
+				continue
+			}
+			if err := autoCommitTranslation(locale, before[locale]); err != nil {
+				log.Error(err)
+				ret = false
+			}
+		}
+	}
+	return ret
+}
+
+// RunAgentTranslate runs the agent to translate "po/XX.po". In batch
+// mode, only untranslated and fuzzy entries are extracted to a smaller
+// file for the agent, and translations are merged back afterwards.
+func RunAgentTranslate(ctx context.Context, agent *Agent, locale string) error {
+	locale, poFile := resolvePoFile(locale)
+	if !Exist(poFile) {
+		return fmt.Errorf(`fail to translate "%s", does not exist`, poFile)
+	}
+
+	if FlagAgentTM() {
+		if err := applyCompendium(locale); err != nil {
+			return err
+		}
+	}
+
+	if !FlagAgentBatch() {
+		if FlagAgentSandbox() {
+			return runAgentSandboxed(ctx, agent, poFile, agentVars(locale))
+		}
+		return runAgentOnFile(ctx, agent, poFile, agentVars(locale))
+	}
+
+	// Extract untranslated and fuzzy entries into a temporary file,
+	// so the agent works on a much smaller input.
+	batchFile, err := extractUntranslatedEntries(poFile)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(batchFile)
+
+	fi, err := os.Stat(batchFile)
+	if err != nil || fi.Size() == 0 {
+		log.Infof(`nothing to translate for "%s", all entries are translated`, poFile)
+		return nil
+	}
+
+	header, entries, err := splitPoEntries(batchFile)
+	if err != nil {
+		return err
+	}
+
+	var state *translateState
+	if FlagAgentResume() {
+		state = loadTranslateState(locale)
+	}
+	if state == nil || state.BatchSize != translateBatchSize {
+		state = &translateState{
+			Locale:    locale,
+			BatchSize: translateBatchSize,
+		}
+	}
+
+	nrBatches := (len(entries) + translateBatchSize - 1) / translateBatchSize
+	bar := newProgressBar(fmt.Sprintf("translate %s", poFile), nrBatches)
+	defer bar.Done()
+	for batchNo := 0; batchNo < nrBatches; batchNo++ {
+		if ctx.Err() != nil {
+			log.Warnf(`translation of "%s" interrupted after %d/%d batches, rerun with --resume to continue`,
+				poFile, len(state.Completed), nrBatches)
+			return ctx.Err()
+		}
+		if state.isCompleted(batchNo) {
+			log.Infof("batch %d/%d of \"%s\" already translated, skipped",
+				batchNo+1, nrBatches, poFile)
+			bar.Add(1)
+			continue
+		}
+		begin := batchNo * translateBatchSize
+		end := begin + translateBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		log.Infof("translating batch %d/%d of \"%s\"",
+			batchNo+1, nrBatches, poFile)
+		EmitProgress(ProgressEvent{
+			Stage:     "translate",
+			Percent:   float64(batchNo) * 100 / float64(nrBatches),
+			Batch:     batchNo + 1,
+			NrBatches: nrBatches,
+			Message:   poFile,
+		})
+		vars := agentVars(locale)
+		vars["batch"] = fmt.Sprintf("%d-%d", begin+1, end)
+		if err = translateBatch(ctx, agent, poFile, header, entries[begin:end], vars); err != nil {
+			return err
+		}
+		state.Completed = append(state.Completed, batchNo)
+		if err = saveTranslateState(state); err != nil {
+			return err
+		}
+		bar.Add(1)
+	}
+	EmitProgress(ProgressEvent{
+		Stage:     "translate",
+		Percent:   100,
+		NrBatches: nrBatches,
+		Message:   poFile,
+	})
+	removeTranslateState()
+	return nil
+}
+
+// translateBatch writes a batch of entries to a temporary po file, runs
+// the agent on it, and merges translations back into the full po file.
+func translateBatch(ctx context.Context, agent *Agent, poFile, header string, entries []string, vars map[string]string) (err error) {
+	endSpan := startSpan("agent/translate-batch", "file", poFile, "entries", len(entries))
+	defer func() { endSpan("error", err != nil) }()
+	tmpFile, err := ioutil.TempFile("", "pofile")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	buf := header + "\n" + strings.Join(entries, "\n")
+	if _, err = tmpFile.WriteString(buf); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	tmpFile.Close()
+	if err = runAgentOnFile(ctx, agent, tmpFile.Name(), vars); err != nil {
+		return err
+	}
+	return mergeTranslatedEntries(poFile, tmpFile.Name())
+}
+
+// splitPoEntries splits a po file into the header and a list of entry
+// blocks, which are separated by blank lines.
+func splitPoEntries(poFile string) (string, []string, error) {
+	var (
+		header  string
+		entries []string
+		block   []string
+	)
+
+	buf, err := ioutil.ReadFile(poFile)
+	if err != nil {
+		return "", nil, err
+	}
+	endBlock := func() {
+		if len(block) == 0 {
+			return
+		}
+		text := strings.Join(block, "\n") + "\n"
+		if header == "" {
+			header = text
+		} else {
+			entries = append(entries, text)
+		}
+		block = nil
+	}
+	for _, line := range strings.Split(string(buf), "\n") {
+		if strings.TrimSpace(line) == "" {
+			endBlock()
+			continue
+		}
+		block = append(block, line)
+	}
+	endBlock()
+	if header == "" {
+		return "", nil, fmt.Errorf(`fail to parse "%s", no header found`, poFile)
+	}
+	return header, entries, nil
+}
+
+func runAgentOnFile(ctx context.Context, agent *Agent, fileName string, vars map[string]string) error {
+	switch level := FlagAgentOutput(); level {
+	case "", AgentOutputQuiet, AgentOutputSummary, AgentOutputFull:
+	default:
+		return fmt.Errorf(
+			`invalid --agent-output "%s", expect "quiet", "summary" or "full"`, level)
+	}
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	vars["source"] = fileName
+	locked := lockedEntries(fileName)
+	oldPo, _ := LoadPoFile(fileName)
+	statusBefore, _ := gitStatusSnapshot()
+
+	var (
+		maxAttempts = translateMaxAttempts()
+		result      = AgentRunResult{RunID: RunID(), Agent: agent.Name, File: fileName}
+		followup    string
+	)
+	for attempt := 1; ; attempt++ {
+		result.Attempts = attempt
+		if err := invokeAgentOnFile(ctx, agent, fileName, vars, followup); err != nil {
+			return err
+		}
+		if FlagAgentDryRun() {
+			return nil
+		}
+		if err := runValidators(agent, oldPo, fileName); err != nil {
+			return err
+		}
+		remaining := remainingMsgIDs(fileName)
+		result.Remaining = len(remaining)
+		if len(remaining) == 0 || attempt >= maxAttempts {
+			break
+		}
+		log.Warnf(`%d entries of "%s" still untranslated or fuzzy, re-prompting agent "%s" (attempt %d/%d)`,
+			len(remaining), fileName, agent.Name, attempt+1, maxAttempts)
+		followup = followupTranslatePrompt(remaining)
+	}
+	result.Violations = agentPolicyViolations(statusBefore)
+	saveRunResult(&result)
+	if result.Remaining > 0 && maxAttempts > 1 {
+		log.Warnf(`%d entries of "%s" remain untranslated or fuzzy after %d attempts`,
+			result.Remaining, fileName, result.Attempts)
+	}
+	if len(result.Violations) > 0 {
+		for _, path := range result.Violations {
+			log.Warnf(`agent "%s" touched "%s", which is outside the allowed files`,
+				agent.Name, path)
+		}
+		if FlagStrict() {
+			return fmt.Errorf(`agent "%s" modified %d files outside the allowed list`,
+				agent.Name, len(result.Violations))
+		}
+	}
+	return enforceLockedEntries(fileName, locked)
+}
+
+// invokeAgentOnFile runs the agent once on a po file. A non-empty
+// followup prompt is delivered on repeated translation attempts.
+// Transient failures of the agent (quota or server errors) only
+// surface as non-zero exit codes, so retry with exponential backoff
+// before counting the invocation as failed.
+func invokeAgentOnFile(ctx context.Context, agent *Agent, fileName string, vars map[string]string, followup string) (err error) {
+	var (
+		retries = AgentRetries()
+		backoff = AgentRetryBackoff()
+	)
+
+	addCounter("agent.invocations", 1)
+	endSpan := startSpan("agent/invoke", "agent", agent.Name, "file", fileName)
+	defer func() {
+		if err != nil {
+			addCounter("agent.failures", 1)
+		}
+		endSpan("error", err != nil)
+	}()
+	if dir := FlagAgentReplay(); dir != "" {
+		return replayAgentStep(dir, fileName)
+	}
+	for attempt := 0; ; attempt++ {
+		if agent.IsSim() {
+			if FlagAgentDryRun() {
+				fmt.Printf("would replay sim agent \"%s\" (scenario %s) on \"%s\"\n",
+					agent.Name, agent.Scenario, fileName)
+				return nil
+			}
+			log.Infof(`replaying sim agent "%s" (scenario %s) on "%s"`,
+				agent.Name, agent.Scenario, fileName)
+			_, err = runSimAgent(ctx, agent, vars)
+		} else if agent.IsAPI() {
+			if FlagAgentDryRun() {
+				fmt.Printf("would call api agent \"%s\" (model %s) on \"%s\"\n",
+					agent.Name, agent.Model, fileName)
+				return nil
+			}
+			log.Infof(`calling api agent "%s" (model %s) on "%s"`,
+				agent.Name, agent.Model, fileName)
+			err = runAPIAgentOnFile(ctx, agent, fileName, followup)
+		} else {
+			var (
+				cmd     *exec.Cmd
+				cleanup func()
+				prompt  string
+			)
+
+			if prompt, err = translatePrompt(agent); err != nil {
+				return err
+			}
+			if prompt != "" {
+				prompt, err = expandPromptTemplate(
+					prompt, newPromptContext(vars["lang"], fileName))
+				if err != nil {
+					return err
+				}
+			}
+			switch {
+			case followup != "":
+				cmd, cleanup, err = agent.CommandWithPrompt(vars, followup)
+			case prompt != "":
+				cmd, cleanup, err = agent.CommandWithPrompt(vars, prompt)
+			default:
+				cmd, err = agent.Command(vars)
+			}
+			if err != nil {
+				return err
+			}
+			if cleanup != nil {
+				defer cleanup()
+			}
+			if FlagAgentDryRun() {
+				fmt.Printf("would run agent \"%s\":\n    %s\n",
+					agent.Name, strings.Join(cmd.Args, " "))
+				return nil
+			}
+			log.Infof(`running agent "%s" on "%s"`, agent.Name, fileName)
+			saveAgentLog("cmd.txt", []byte(strings.Join(cmd.Args, " ")+"\n"))
+			display := newAgentOutputWriter(agentConsole(os.Stdout),
+				AgentParseOptions{Level: FlagAgentOutput()})
+			defer display.Close()
+			cmd.Stdout = display
+			cmd.Stderr = agentConsole(os.Stderr)
+			if logFile := newAgentLogFile("output.log"); logFile != nil {
+				// The log file keeps the raw output of the agent.
+				cmd.Stdout = io.MultiWriter(display, logFile)
+				cmd.Stderr = io.MultiWriter(agentConsole(os.Stderr), logFile)
+				defer logFile.Close()
+			}
+			var (
+				recStdout, recStderr bytes.Buffer
+				recBefore            []byte
+			)
+			if FlagAgentRecord() != "" {
+				recBefore, _ = ioutil.ReadFile(fileName)
+				cmd.Stdout = io.MultiWriter(cmd.Stdout, &recStdout)
+				cmd.Stderr = io.MultiWriter(cmd.Stderr, &recStderr)
+			}
+			err = runAgentCmd(ctx, cmd)
+			display.Close()
+			if streamErr := display.Err(); streamErr != nil {
+				if err != nil {
+					err = fmt.Errorf("%s; %s", err, streamErr)
+				} else {
+					log.Warnf(`agent "%s": %s`, agent.Name, streamErr)
+				}
+			}
+			if err == nil && FlagAgentRecord() != "" {
+				recordAgentStep(fileName, recStdout.Bytes(),
+					recStderr.Bytes(), recBefore)
+			}
+		}
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= retries {
+			return fmt.Errorf(`fail to run agent "%s": %s`, agent.Name, err)
+		}
+		log.Warnf(`agent "%s" failed (%s), retry %d/%d after %s`,
+			agent.Name, err, attempt+1, retries, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// runAgentCmd starts the agent command and waits for it to finish. If
+// the context is canceled, the whole process group of the agent is
+// killed, so no orphaned children are left behind.
+func runAgentCmd(ctx context.Context, cmd *exec.Cmd) error {
+	setAgentProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-ctx.Done():
+		killAgentProcessGroup(cmd)
+		<-done
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
+// extractUntranslatedEntries extracts untranslated and fuzzy entries of
+// a po file into a temporary file using msgattrib and msgcat.
+func extractUntranslatedEntries(poFile string) (string, error) {
+	var parts []string
+
+	for _, opt := range []string{"--untranslated", "--only-fuzzy"} {
+		tmpFile, err := ioutil.TempFile("", "pofile")
+		if err != nil {
+			return "", err
+		}
+		tmpFile.Close()
+		parts = append(parts, tmpFile.Name())
+		cmd := exec.Command("msgattrib",
+			opt,
+			"--no-obsolete",
+			"-o",
+			tmpFile.Name(),
+			poFile)
+		cmd.Dir = GitRootDir
+		if err = cmd.Run(); err != nil {
+			removeFiles(parts)
+			return "", fmt.Errorf("fail to run msgattrib %s: %s", opt, err)
+		}
+	}
+	defer removeFiles(parts)
+
+	batchFile, err := ioutil.TempFile("", "pofile")
+	if err != nil {
+		return "", err
+	}
+	batchFile.Close()
+	cmd := exec.Command("msgcat",
+		append([]string{"--use-first", "-o", batchFile.Name()}, parts...)...)
+	cmd.Dir = GitRootDir
+	if err = cmd.Run(); err != nil {
+		os.Remove(batchFile.Name())
+		return "", fmt.Errorf("fail to run msgcat: %s", err)
+	}
+	return batchFile.Name(), nil
+}
+
+// mergeTranslatedEntries merges translations in the batch file back into
+// the full po file using msgcat.
+func mergeTranslatedEntries(poFile, batchFile string) error {
+	tmpFile, err := ioutil.TempFile(filepath.Dir(poFile), "pofile")
+	if err != nil {
+		return err
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+	cmd := exec.Command("msgcat",
+		"--use-first",
+		"-o",
+		tmpFile.Name(),
+		batchFile,
+		poFile)
+	cmd.Dir = GitRootDir
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("fail to run msgcat: %s", err)
+	}
+	return os.Rename(tmpFile.Name(), poFile)
+}
+
+func removeFiles(fileNames []string) {
+	for _, fileName := range fileNames {
+		os.Remove(fileName)
+	}
+}
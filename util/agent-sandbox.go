@@ -0,0 +1,62 @@
+package util
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// copyFile copies a regular file, creating missing parent directories
+// of the destination.
+func copyFile(dst, src string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	fin, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer fin.Close()
+	fout, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer fout.Close()
+	_, err = io.Copy(fout, fin)
+	return err
+}
+
+// runAgentSandboxed executes the agent in a temporary directory which
+// contains only the po files the run needs, and copies the translated
+// po file back only after it passed validation. Source code of the
+// repository is out of reach of the agent.
+func runAgentSandboxed(ctx context.Context, agent *Agent, poFile string, vars map[string]string) error {
+	sandbox, err := ioutil.TempDir("", "git-po-helper-sandbox-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(sandbox)
+
+	for _, fileName := range []string{filepath.Join(PoDir, GitPot), poFile} {
+		if !Exist(fileName) {
+			continue
+		}
+		if err = copyFile(filepath.Join(sandbox, fileName), fileName); err != nil {
+			return err
+		}
+	}
+	sandboxFile := filepath.Join(sandbox, poFile)
+	vars["workdir"] = sandbox
+	vars["potfile"] = filepath.Join(sandbox, PoDir, GitPot)
+	log.Infof(`running agent "%s" in sandbox "%s"`, agent.Name, sandbox)
+	if err = runAgentOnFile(ctx, agent, sandboxFile, vars); err != nil {
+		return err
+	}
+	// Copy back only the expected output file, nothing else the agent
+	// may have created in the sandbox.
+	return copyFile(poFile, sandboxFile)
+}
@@ -0,0 +1,215 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// AgentKindSim marks a simulated agent which replays a scripted
+// scenario instead of running a real LLM, so the whole agent-run and
+// agent-test pipeline can be integration-tested deterministically.
+const AgentKindSim = "sim"
+
+// simScenario is the scripted scenario of a simulated agent, loaded
+// from the YAML file named by config variable "agents.<name>.scenario".
+type simScenario struct {
+	Steps []simStep `yaml:"steps"`
+}
+
+// simStep is one scripted action of a simulation scenario. Placeholder
+// variables such as "{source}" and "{lang}" are expanded in the output
+// and file fields.
+type simStep struct {
+	// Output is replayed to stdout, line by line, like the JSONL
+	// stream of a real agent.
+	Output string `yaml:"output"`
+
+	// Stderr is replayed to stderr.
+	Stderr string `yaml:"stderr"`
+
+	// Delay pauses the simulation, such as "500ms".
+	Delay time.Duration `yaml:"delay"`
+
+	// Write modifies a file, like an agent editing the worktree.
+	Write *simWrite `yaml:"write"`
+
+	// Fill marks the untranslated and fuzzy entries of a po file as
+	// translated, by copying each msgid into its msgstr.
+	Fill string `yaml:"fill"`
+
+	// Exit ends the simulation with the given exit code.
+	Exit *int `yaml:"exit"`
+}
+
+// simWrite is a scripted file modification.
+type simWrite struct {
+	File    string `yaml:"file"`
+	Content string `yaml:"content"`
+	Append  bool   `yaml:"append"`
+}
+
+// newSimAgent creates a "sim" kind agent from the config section of
+// the given prefix.
+func newSimAgent(name, prefix string) (*Agent, error) {
+	agent := &Agent{
+		Name:     name,
+		Kind:     AgentKindSim,
+		Scenario: viper.GetString(prefix + ".scenario"),
+	}
+	if agent.Scenario == "" {
+		return nil, fmt.Errorf(
+			`sim agent "%s" needs "%s.scenario" in the config file`,
+			name, prefix)
+	}
+	return agent, nil
+}
+
+// IsSim indicates the agent replays a scripted scenario instead of
+// running a real LLM.
+func (v *Agent) IsSim() bool {
+	return v.Kind == AgentKindSim
+}
+
+// loadSimScenario reads and parses the scenario file of a simulated
+// agent.
+func loadSimScenario(fileName string) (*simScenario, error) {
+	buf, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read scenario file: %s", err)
+	}
+	scenario := simScenario{}
+	if err := yaml.UnmarshalStrict(buf, &scenario); err != nil {
+		return nil, fmt.Errorf(`fail to parse scenario file "%s": %s`, fileName, err)
+	}
+	return &scenario, nil
+}
+
+// expandSimVars expands placeholder variables in a scenario field.
+func expandSimVars(text string, vars map[string]string) string {
+	for name, value := range vars {
+		text = strings.Replace(text, "{"+name+"}", value, -1)
+	}
+	return text
+}
+
+// simFillPoFile copies the msgid of every untranslated entry into its
+// msgstr and drops fuzzy markers, simulating a perfect-recall
+// translator.
+func simFillPoFile(fileName string) error {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return err
+	}
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || entry.IsLocked() {
+			continue
+		}
+		if !entry.IsTranslated() {
+			entry.MsgStr = entry.MsgID
+			for i := range entry.MsgStrPlural {
+				if entry.MsgStrPlural[i] == "" {
+					entry.MsgStrPlural[i] = entry.MsgID
+				}
+			}
+		}
+		if entry.IsFuzzy() {
+			var comments []string
+			for _, comment := range entry.Comments {
+				if strings.HasPrefix(comment, "#,") {
+					var flags []string
+					for _, flag := range strings.Split(comment[2:], ",") {
+						if flag = strings.TrimSpace(flag); flag != "" && flag != "fuzzy" {
+							flags = append(flags, flag)
+						}
+					}
+					if len(flags) == 0 {
+						continue
+					}
+					comment = "#, " + strings.Join(flags, ", ")
+				}
+				comments = append(comments, comment)
+			}
+			entry.Comments = comments
+		}
+	}
+	return poFile.Save(fileName)
+}
+
+// runSimAgent replays the scenario of a simulated agent, and returns
+// the replayed stdout, like the report of a real agent.
+func runSimAgent(ctx context.Context, agent *Agent, vars map[string]string) (string, error) {
+	scenario, err := loadSimScenario(agent.Scenario)
+	if err != nil {
+		return "", err
+	}
+	var output strings.Builder
+	display := agentConsole(os.Stdout)
+	for i, step := range scenario.Steps {
+		if ctx.Err() != nil {
+			return output.String(), ctx.Err()
+		}
+		if step.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return output.String(), ctx.Err()
+			case <-time.After(step.Delay):
+			}
+		}
+		if step.Output != "" {
+			text := expandSimVars(step.Output, vars)
+			output.WriteString(text)
+			fmt.Fprint(display, text)
+		}
+		if step.Stderr != "" {
+			fmt.Fprint(agentConsole(os.Stderr), expandSimVars(step.Stderr, vars))
+		}
+		if step.Write != nil {
+			fileName := expandSimVars(step.Write.File, vars)
+			content := []byte(expandSimVars(step.Write.Content, vars))
+			if step.Write.Append {
+				f, err := os.OpenFile(fileName,
+					os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+				if err != nil {
+					return output.String(), err
+				}
+				_, err = f.Write(content)
+				f.Close()
+				if err != nil {
+					return output.String(), err
+				}
+			} else {
+				if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+					return output.String(), err
+				}
+				if err := ioutil.WriteFile(fileName, content, 0644); err != nil {
+					return output.String(), err
+				}
+			}
+		}
+		if step.Fill != "" {
+			if err := simFillPoFile(expandSimVars(step.Fill, vars)); err != nil {
+				return output.String(), err
+			}
+		}
+		if step.Exit != nil {
+			if *step.Exit != 0 {
+				return output.String(), fmt.Errorf(
+					"sim agent exited with code %d at step %d", *step.Exit, i+1)
+			}
+			break
+		}
+	}
+	log.Debugf(`sim agent "%s" replayed %d steps of "%s"`,
+		agent.Name, len(scenario.Steps), agent.Scenario)
+	saveAgentLog("sim.log", []byte(output.String()))
+	return output.String(), nil
+}
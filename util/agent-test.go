@@ -0,0 +1,238 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AgentTestResult is the outcome of benchmarking an agent on one po
+// file.
+type AgentTestResult struct {
+	Agent       string  `json:"agent"`
+	Locale      string  `json:"locale"`
+	Score       int     `json:"score"`
+	Translated  int     `json:"translated"`
+	Total       int     `json:"total"`
+	DurationSec float64 `json:"duration_sec"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// CmdAgentTest implements agent-test sub command. Every po file is
+// copied to a scratch directory, translated by the agent, and scored
+// by the completeness and syntax of the result, so different agents
+// can be benchmarked without touching the real po files.
+func CmdAgentTest(ctx context.Context, args ...string) bool {
+	var (
+		agents  []*Agent
+		results []AgentTestResult
+		ret     = true
+	)
+
+	installRunIDHook()
+	startTUI()
+	defer stopTUI()
+	defer flushTelemetry()
+	if names := FlagAgentTestAgents(); len(names) > 0 {
+		for _, name := range names {
+			agent, err := NewNamedAgent(name)
+			if err != nil {
+				log.Error(err)
+				return false
+			}
+			agents = append(agents, agent)
+		}
+	} else {
+		agent, err := NewAgentFromFlags()
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		agents = append(agents, agent)
+	}
+	if len(args) == 0 {
+		log.Errorf(`nothing to test, please provide "XX.po" files`)
+		return false
+	}
+	for _, agent := range agents {
+		if ctx.Err() != nil {
+			log.Warnf("benchmark interrupted, showing partial summary")
+			ret = false
+			break
+		}
+		for _, fileName := range args {
+			if ctx.Err() != nil {
+				ret = false
+				break
+			}
+			locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+			if _, err := GetPrettyLocaleName(locale); err != nil {
+				log.Error(err)
+				ret = false
+				continue
+			}
+			setTUIStatus(agent.Name+"/"+locale, "benchmarking")
+			result := benchmarkAgent(ctx, agent, locale)
+			if result.Error != "" {
+				setTUIStatus(agent.Name+"/"+locale, "failed: "+result.Error)
+				ret = false
+			} else {
+				setTUIStatus(agent.Name+"/"+locale, "done")
+			}
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return false
+	}
+	stopTUI()
+
+	runDir := filepath.Join(HelperDir, "runs", RunID())
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		log.Error(err)
+		return false
+	}
+	pruneOldRuns(filepath.Join(HelperDir, "runs"))
+	buf, err := json.MarshalIndent(results, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(runDir, "results.json"), append(buf, '\n'), 0644)
+	}
+	if err != nil {
+		log.Errorf("fail to save benchmark results: %s", err)
+		ret = false
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Agent\tLanguage\tScore\tTranslated\tDuration\tResult")
+	for _, result := range results {
+		status := "ok"
+		if result.Error != "" {
+			status = result.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d/%d\t%.1fs\t%s\n",
+			result.Agent, result.Locale, result.Score,
+			result.Translated, result.Total, result.DurationSec, status)
+	}
+	w.Flush()
+
+	if len(agents) > 1 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "Agent\tRuns\tFailed\tAvg Score\tWall Time")
+		for _, agent := range agents {
+			var (
+				runs, failed, score int
+				duration            float64
+			)
+			for _, result := range results {
+				if result.Agent != agent.Name {
+					continue
+				}
+				runs++
+				if result.Error != "" {
+					failed++
+				}
+				score += result.Score
+				duration += result.DurationSec
+			}
+			if runs == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1fs\n",
+				agent.Name, runs, failed, score/runs, duration)
+		}
+		w.Flush()
+	}
+
+	recordBenchmarkResults("translate", results)
+
+	for _, agent := range agents {
+		var (
+			runs, score int
+			duration    float64
+			ok          = true
+		)
+		for _, result := range results {
+			if result.Agent != agent.Name {
+				continue
+			}
+			runs++
+			score += result.Score
+			duration += result.DurationSec
+			if result.Error != "" {
+				ok = false
+			}
+		}
+		if runs == 0 {
+			continue
+		}
+		Notify(&NotifySummary{
+			Action:      "agent-test",
+			Agent:       agent.Name,
+			Files:       strings.Join(args, " "),
+			OK:          ok,
+			Score:       score / runs,
+			DurationSec: duration,
+		})
+	}
+
+	if htmlFile := FlagAgentTestHTMLReport(); htmlFile != "" {
+		if err := WriteHTMLReport(htmlFile, results); err != nil {
+			log.Errorf("fail to write html report: %s", err)
+			ret = false
+		}
+	}
+	return ret
+}
+
+// benchmarkAgent translates a scratch copy of "po/XX.po" and scores
+// the result.
+func benchmarkAgent(ctx context.Context, agent *Agent, locale string) AgentTestResult {
+	result := AgentTestResult{
+		Agent:  agent.Name,
+		Locale: locale,
+	}
+	fail := func(err error) AgentTestResult {
+		result.Error = err.Error()
+		return result
+	}
+
+	poFile := filepath.Join(PoDir, locale+".po")
+	buf, err := ioutil.ReadFile(poFile)
+	if err != nil {
+		return fail(err)
+	}
+	scratchDir, err := ioutil.TempDir("", "agent-test")
+	if err != nil {
+		return fail(err)
+	}
+	defer os.RemoveAll(scratchDir)
+	scratchFile := filepath.Join(scratchDir, locale+".po")
+	if err = ioutil.WriteFile(scratchFile, buf, 0644); err != nil {
+		return fail(err)
+	}
+
+	start := time.Now()
+	err = runAgentOnFile(ctx, agent, scratchFile, agentVars(locale))
+	result.DurationSec = time.Since(start).Seconds()
+	if err != nil {
+		return fail(err)
+	}
+
+	stats, err := StatPoFile(scratchFile)
+	if err != nil {
+		return fail(err)
+	}
+	result.Translated = stats.Translated
+	result.Total = stats.Total
+	result.Score = int(stats.Percent())
+	return result
+}
@@ -0,0 +1,232 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+
+	// knownPlaceholders are the placeholder variables which may appear
+	// in agent command templates and prompts.
+	knownPlaceholders = map[string]bool{
+		"{prompt}":      true,
+		"{prompt_file}": true,
+		"{source}":      true,
+		"{lang}":        true,
+		"{potfile}":     true,
+		"{workdir}":     true,
+		"{batch}":       true,
+	}
+)
+
+// Agent holds settings of an external agent which can translate or
+// review entries of a po file.
+type Agent struct {
+	// Name of the agent, for display purpose.
+	Name string
+
+	// Kind of the agent: "cmd" (the default) runs an external command,
+	// "api" calls an OpenAI-compatible chat completion endpoint
+	// directly, and "sim" replays a scripted scenario.
+	Kind string
+
+	// Cmd is the command template of a "cmd" kind agent. Placeholder
+	// variables, such as "{source}", are expanded before execution.
+	Cmd string
+
+	// BaseURL is the endpoint of an "api" kind agent, such as
+	// "https://api.openai.com/v1".
+	BaseURL string
+
+	// Model is the model name sent to the endpoint of an "api" kind
+	// agent.
+	Model string
+
+	// APIKeyEnv names the environment variable holding the API key of
+	// an "api" kind agent, so the key itself never appears in config
+	// files.
+	APIKeyEnv string
+
+	// Prompt overrides the default prompt of translate and review
+	// runs, resolved from options "--prompt" and "--prompt-file".
+	Prompt string
+
+	// Env holds extra environment variables for the agent, such as
+	// API keys or proxy settings. Values support "${VAR}" expansion
+	// from the parent environment.
+	Env map[string]string
+
+	// Scenario is the YAML scenario file of a "sim" kind agent.
+	Scenario string
+
+	// PromptVia defines how the prompt is delivered to the agent:
+	// "arg" expands the "{prompt}" placeholder (the default), "stdin"
+	// pipes the prompt to the command, and "file" writes the prompt
+	// to a temporary file referenced by the "{prompt_file}"
+	// placeholder. Long prompts delivered via argv may hit ARG_MAX
+	// limits and leak into process listings.
+	PromptVia string
+}
+
+// NewAgentFromFlags creates an agent from command line options and the
+// "agent" section of the config file.
+func NewAgentFromFlags() (*Agent, error) {
+	switch viper.GetString("agent.kind") {
+	case AgentKindAPI:
+		return newAPIAgent("api", "agent")
+	case AgentKindAider:
+		return newAiderAgent("aider", "agent")
+	case AgentKindSim:
+		return newSimAgent("sim", "agent")
+	}
+	cmd := FlagAgentCmd()
+	if cmd == "" {
+		return nil, fmt.Errorf(`no agent defined, please provide option "--agent-cmd"`)
+	}
+	if err := checkAgentPlaceholders(cmd); err != nil {
+		return nil, err
+	}
+	return &Agent{
+		Name:      strings.Fields(cmd)[0],
+		Cmd:       cmd,
+		Env:       viper.GetStringMapString("agent.env"),
+		PromptVia: viper.GetString("agent.prompt_via"),
+	}, nil
+}
+
+// NewNamedAgent creates an agent from config section "agents.<name>",
+// which holds the kind, command template, and optional env and
+// prompt_via settings of a named agent.
+func NewNamedAgent(name string) (*Agent, error) {
+	prefix := "agents." + name
+	switch viper.GetString(prefix + ".kind") {
+	case AgentKindAPI:
+		return newAPIAgent(name, prefix)
+	case AgentKindAider:
+		return newAiderAgent(name, prefix)
+	case AgentKindSim:
+		return newSimAgent(name, prefix)
+	}
+	cmd := viper.GetString(prefix + ".cmd")
+	if cmd == "" {
+		return nil, fmt.Errorf(
+			`agent "%s" is not defined, please add "agents.%s.cmd" to the config file`,
+			name, name)
+	}
+	if err := checkAgentPlaceholders(cmd); err != nil {
+		return nil, err
+	}
+	return &Agent{
+		Name:      name,
+		Cmd:       cmd,
+		Env:       viper.GetStringMapString(prefix + ".env"),
+		PromptVia: viper.GetString(prefix + ".prompt_via"),
+	}, nil
+}
+
+// AgentNames returns the names of the agents defined in config section
+// "agents", sorted, for listings and shell completion.
+func AgentNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("agents") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkAgentPlaceholders catches typos in placeholder names of an agent
+// command template, instead of passing literal braces to the agent.
+func checkAgentPlaceholders(cmd string) error {
+	for _, name := range placeholderPattern.FindAllString(cmd, -1) {
+		if !knownPlaceholders[name] {
+			return fmt.Errorf(
+				`unknown placeholder "%s" in agent command template`, name)
+		}
+	}
+	return nil
+}
+
+// agentVars returns the common placeholder variables for running an
+// agent on a po file of the given locale.
+func agentVars(locale string) map[string]string {
+	return map[string]string{
+		"lang":    locale,
+		"potfile": filepath.Join(PoDir, GitPot),
+		"workdir": GitRootDir,
+	}
+}
+
+// CommandWithPrompt creates the command for execution, delivering the
+// prompt in the way defined by the PromptVia setting of the agent. The
+// returned cleanup function must be called after the command finishes.
+func (v *Agent) CommandWithPrompt(vars map[string]string, prompt string) (*exec.Cmd, func(), error) {
+	cleanup := func() {}
+
+	switch v.PromptVia {
+	case "", "arg":
+		vars["prompt"] = prompt
+	case "stdin":
+		// Deliver the prompt below, after the command is created.
+	case "file":
+		tmpFile, err := ioutil.TempFile("", "prompt")
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, err = tmpFile.WriteString(prompt); err != nil {
+			tmpFile.Close()
+			os.Remove(tmpFile.Name())
+			return nil, nil, err
+		}
+		tmpFile.Close()
+		vars["prompt_file"] = tmpFile.Name()
+		cleanup = func() { os.Remove(tmpFile.Name()) }
+	default:
+		return nil, nil, fmt.Errorf(
+			`invalid prompt_via "%s" for agent "%s", expect "arg", "stdin" or "file"`,
+			v.PromptVia, v.Name)
+	}
+	cmd, err := v.Command(vars)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if v.PromptVia == "stdin" {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+	return cmd, cleanup, nil
+}
+
+// Command expands placeholder variables in the agent command template,
+// and creates the command for execution.
+func (v *Agent) Command(vars map[string]string) (*exec.Cmd, error) {
+	var args []string
+
+	for _, field := range strings.Fields(v.Cmd) {
+		for key, value := range vars {
+			field = strings.Replace(field, "{"+key+"}", value, -1)
+		}
+		args = append(args, field)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command for agent \"%s\"", v.Name)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	if len(v.Env) > 0 {
+		cmd.Env = os.Environ()
+		for key, value := range v.Env {
+			cmd.Env = append(cmd.Env, key+"="+os.ExpandEnv(value))
+		}
+	}
+	return cmd, nil
+}
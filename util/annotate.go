@@ -0,0 +1,67 @@
+package util
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// notePrefix marks reviewer notes in translator comments.
+const notePrefix = "# note: "
+
+// AnnotateEntry inserts or updates the reviewer note of the entry with
+// the given msgid, and returns false if no entry matches.
+func AnnotateEntry(poFile *PoFile, msgID, note string) bool {
+	for _, entry := range poFile.Entries {
+		if entry.MsgID != msgID {
+			continue
+		}
+		for i, comment := range entry.Comments {
+			if strings.HasPrefix(comment, notePrefix) {
+				entry.Comments[i] = notePrefix + note
+				return true
+			}
+		}
+		// Keep notes before machine generated comments (#., #:, #,).
+		idx := len(entry.Comments)
+		for i, comment := range entry.Comments {
+			if strings.HasPrefix(comment, "#.") ||
+				strings.HasPrefix(comment, "#:") ||
+				strings.HasPrefix(comment, "#,") {
+				idx = i
+				break
+			}
+		}
+		comments := make([]string, 0, len(entry.Comments)+1)
+		comments = append(comments, entry.Comments[:idx]...)
+		comments = append(comments, notePrefix+note)
+		comments = append(comments, entry.Comments[idx:]...)
+		entry.Comments = comments
+		return true
+	}
+	return false
+}
+
+// CmdAnnotate implements annotate sub command.
+func CmdAnnotate(fileName string) bool {
+	msgID := FlagAnnotateMsgID()
+	note := FlagAnnotateNote()
+	if msgID == "" || note == "" {
+		log.Errorf(`please provide both "--msgid" and "--note" options`)
+		return false
+	}
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if !AnnotateEntry(poFile, msgID, note) {
+		log.Errorf(`no entry with msgid "%s" in "%s"`, msgID, fileName)
+		return false
+	}
+	if err = poFile.Save(fileName); err != nil {
+		log.Error(err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultCommitMessageTemplate is the subject of auto-created l10n
+// commits, following the convention of the git-l10n workflow.
+const defaultCommitMessageTemplate = "l10n: {lang}: translate {count} new messages"
+
+// nrTranslated returns the number of translated, non-fuzzy entries of
+// a po file.
+func nrTranslated(fileName string) int {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" {
+			continue
+		}
+		if entry.IsTranslated() && !entry.IsFuzzy() {
+			count++
+		}
+	}
+	return count
+}
+
+// autoCommitFiles stages the given files and creates a commit with the
+// given message. Nothing is committed when the files have no changes.
+func autoCommitFiles(message string, files ...string) error {
+	args := append([]string{"status", "--porcelain", "--"}, files...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("fail to check status: %s", err)
+	}
+	if strings.TrimSpace(string(buf)) == "" {
+		log.Infof(`nothing to commit for "%s"`, strings.Join(files, " "))
+		return nil
+	}
+
+	args = append([]string{"add", "--"}, files...)
+	cmd = exec.Command("git", args...)
+	cmd.Dir = GitRootDir
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("fail to stage files: %s", err)
+	}
+
+	args = []string{"commit", "-m", message}
+	if FlagCommitSignoff() {
+		args = append(args, "-s")
+	}
+	if FlagCommitGPGSign() {
+		args = append(args, "-S")
+	}
+	cmd = exec.Command("git", args...)
+	cmd.Dir = GitRootDir
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("fail to commit: %s", err)
+	}
+	log.Infof(`created commit: %s`, message)
+	return nil
+}
+
+// autoCommitTranslation commits "po/XX.po" with an l10n commit message
+// computed from how many entries became translated during the run.
+func autoCommitTranslation(locale string, nrBefore int) error {
+	poFile := filepath.Join(PoDir, locale+".po")
+	count := nrTranslated(poFile) - nrBefore
+	if count < 0 {
+		count = 0
+	}
+	message := FlagCommitMessageTemplate()
+	if message == "" {
+		if count == 0 {
+			message = "l10n: {lang}: update translations"
+		} else {
+			message = defaultCommitMessageTemplate
+		}
+	}
+	message = strings.Replace(message, "{lang}", locale, -1)
+	message = strings.Replace(message, "{count}", strconv.Itoa(count), -1)
+	return autoCommitFiles(message, poFile)
+}
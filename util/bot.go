@@ -0,0 +1,138 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// botReviewLabel is the PR label which triggers a review in bot mode.
+const botReviewLabel = "l10n-review"
+
+// githubEvent is the subset of the GitHub Actions event payload used
+// in bot mode.
+type githubEvent struct {
+	Action string `json:"action"`
+	Label  struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	PullRequest struct {
+		Number      int    `json:"number"`
+		CommentsURL string `json:"comments_url"`
+		Base        struct {
+			Sha string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			Sha string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+// CmdBot implements bot sub command, which is intended to run in
+// GitHub Actions, and responds to the "l10n-review" label on pull
+// requests which touch po files.
+func CmdBot() bool {
+	eventFile := os.Getenv("GITHUB_EVENT_PATH")
+	if eventFile == "" {
+		log.Error("bot mode must run in GitHub Actions, GITHUB_EVENT_PATH is not set")
+		return false
+	}
+	buf, err := ioutil.ReadFile(eventFile)
+	if err != nil {
+		log.Errorf("fail to read event payload: %s", err)
+		return false
+	}
+	event := githubEvent{}
+	if err = json.Unmarshal(buf, &event); err != nil {
+		log.Errorf("fail to parse event payload: %s", err)
+		return false
+	}
+	if event.Action != "labeled" || event.Label.Name != botReviewLabel {
+		log.Infof(`nothing to do for action "%s", label "%s"`,
+			event.Action, event.Label.Name)
+		return true
+	}
+
+	poFiles, err := changedPoFiles(event.PullRequest.Base.Sha, event.PullRequest.Head.Sha)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if len(poFiles) == 0 {
+		log.Infof("no po files changed in pull request #%d", event.PullRequest.Number)
+		return true
+	}
+
+	agent, err := NewAgentFromFlags()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	var report bytes.Buffer
+	for _, poFile := range poFiles {
+		out, err := RunAgentReview(InterruptContext(), agent, poFile)
+		if err != nil {
+			log.Error(err)
+			fmt.Fprintf(&report, "## %s\n\nreview failed: %s\n\n", poFile, err)
+			continue
+		}
+		fmt.Fprintf(&report, "## %s\n\n%s\n\n", poFile, out)
+	}
+	if err = postGitHubComment(event.PullRequest.CommentsURL, report.String()); err != nil {
+		log.Errorf("fail to post review comment: %s", err)
+		return false
+	}
+	return true
+}
+
+// changedPoFiles returns po files changed between two commits.
+func changedPoFiles(base, head string) ([]string, error) {
+	var poFiles []string
+
+	cmd := exec.Command("git", "diff", "--name-only", base, head)
+	cmd.Dir = GitRootDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("fail to run git diff: %s", err)
+	}
+	for _, fileName := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(fileName, PoDir+"/") && strings.HasSuffix(fileName, ".po") {
+			poFiles = append(poFiles, fileName)
+		}
+	}
+	return poFiles, nil
+}
+
+// postGitHubComment posts a comment using the GitHub API.
+func postGitHubComment(commentsURL, body string) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	buf, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", commentsURL, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status from GitHub API: %s", resp.Status)
+	}
+	return nil
+}
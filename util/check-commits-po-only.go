@@ -0,0 +1,74 @@
+package util
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// commitChangedFiles returns the paths a commit touches.
+func commitChangedFiles(commit string) ([]string, error) {
+	return DefaultRepository().CommitChangedFiles(commit)
+}
+
+// CommitChangedFiles returns the paths a commit touches.
+func (v *Repository) CommitChangedFiles(commit string) ([]string, error) {
+	cmd := v.GitCommand(
+		"diff-tree",
+		"-r",
+		"--name-only",
+		"--no-commit-id",
+		commit)
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// checkCommitPoOnly verifies a commit touches only the po directory,
+// and that an l10n commit modifies exactly one language — a common
+// policy of the git-l10n coordinator for commits in a pull request
+// range.
+func checkCommitPoOnly(commit string) bool {
+	ok := true
+
+	files, err := commitChangedFiles(commit)
+	if err != nil {
+		log.Errorf("commit %s: fail to run git-diff-tree: %s", AbbrevCommit(commit), err)
+		return false
+	}
+	locales := make(map[string]bool)
+	for _, fileName := range files {
+		if !strings.HasPrefix(fileName, PoDir+"/") {
+			log.Errorf(`commit %s: touches "%s", which is outside "%s/"`,
+				AbbrevCommit(commit), fileName, PoDir)
+			ok = false
+			continue
+		}
+		if filepath.Dir(fileName) == PoDir && filepath.Ext(fileName) == ".po" {
+			locales[strings.TrimSuffix(filepath.Base(fileName), ".po")] = true
+		}
+	}
+	if len(locales) > 1 {
+		var names []string
+		for locale := range locales {
+			names = append(names, locale)
+		}
+		sort.Strings(names)
+		log.Errorf(`commit %s: modifies %d languages (%s), expect exactly one`,
+			AbbrevCommit(commit), len(names), strings.Join(names, ", "))
+		ok = false
+	}
+	ReportGate("check-commits/"+AbbrevCommit(commit)+"/po-only", ok,
+		"%d files", len(files))
+	return ok
+}
@@ -629,6 +629,12 @@ func CheckCommit(commit string) int {
 	if !checkCommitLog(commit) {
 		ret |= checkResultError
 	}
+	if FlagStatsConsistency() && !checkCommitStatsConsistency(commit) {
+		ret |= checkResultError
+	}
+	if FlagPoOnly() && !checkCommitPoOnly(commit) {
+		ret |= checkResultError
+	}
 	return ret
 }
 
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// CmdCheckConfig implements agent-run check-config sub command. It
+// validates the configuration file and prints a pass/fail checklist,
+// so problems surface all at once instead of one at a time deep
+// inside agent runs.
+func CmdCheckConfig() bool {
+	ret := true
+
+	if fileName := viper.ConfigFileUsed(); fileName != "" {
+		log.Infof(`checking config file "%s"`, fileName)
+	} else {
+		log.Warnf("no config file found, checking defaults only")
+	}
+
+	if !checkAgentConfig("agent", "agent") {
+		ret = false
+	}
+	for name := range viper.GetStringMap("agents") {
+		if !checkAgentConfig("agents."+name, "agents."+name) {
+			ret = false
+		}
+	}
+
+	if _, err := SelectValidators(); err != nil {
+		ReportGate("config/validators", false, "%s", err)
+		log.Error(err)
+		ret = false
+	} else {
+		ReportGate("config/validators", true, "ok")
+	}
+
+	switch level := FlagAgentOutput(); level {
+	case "", AgentOutputQuiet, AgentOutputSummary, AgentOutputFull:
+		ReportGate("config/agent-output", true, "ok")
+	default:
+		ReportGate("config/agent-output", false, `invalid level "%s"`, level)
+		log.Errorf(`invalid agent output level "%s", expect "quiet", "summary" or "full"`, level)
+		ret = false
+	}
+
+	promptOK := true
+	for _, name := range configPromptNames {
+		if _, err := configPrompt(name); err != nil {
+			log.Error(err)
+			promptOK = false
+		}
+	}
+	ReportGate("config/prompts", promptOK, "ok")
+	if !promptOK {
+		ret = false
+	}
+
+	reviewOK := true
+	for _, key := range []string{
+		"review.batch_size",
+		"review.batch_threshold",
+		"review.max_parallel_batches",
+		"translate.max_attempts",
+	} {
+		if viper.GetInt(key) < 0 {
+			log.Errorf(`config variable "%s" must not be negative`, key)
+			reviewOK = false
+		}
+	}
+	ReportGate("config/limits", reviewOK, "ok")
+	if !reviewOK {
+		ret = false
+	}
+
+	return ret
+}
+
+// checkAgentConfig validates the agent settings of one config section,
+// and records one gate for the section.
+func checkAgentConfig(name, prefix string) bool {
+	var errs []error
+
+	kind := viper.GetString(prefix + ".kind")
+	switch kind {
+	case "", "cmd", AgentKindAPI, AgentKindAider, AgentKindSim:
+	default:
+		errs = append(errs, fmt.Errorf(
+			`invalid kind "%s", expect "cmd", "%s", "%s" or "%s"`,
+			kind, AgentKindAPI, AgentKindAider, AgentKindSim))
+	}
+
+	switch promptVia := viper.GetString(prefix + ".prompt_via"); promptVia {
+	case "", "arg", "stdin", "file":
+	default:
+		errs = append(errs, fmt.Errorf(
+			`invalid prompt_via "%s", expect "arg", "stdin" or "file"`, promptVia))
+	}
+
+	switch kind {
+	case AgentKindAPI:
+		if viper.GetString(prefix+".base_url") == "" {
+			errs = append(errs, fmt.Errorf(`missing "%s.base_url"`, prefix))
+		}
+		if viper.GetString(prefix+".model") == "" {
+			errs = append(errs, fmt.Errorf(`missing "%s.model"`, prefix))
+		}
+		if env := viper.GetString(prefix + ".api_key_env"); env != "" && os.Getenv(env) == "" {
+			errs = append(errs, fmt.Errorf(
+				`environment variable "%s" of "%s.api_key_env" is not set`, env, prefix))
+		}
+	case AgentKindAider:
+		if _, err := exec.LookPath("aider"); err != nil {
+			errs = append(errs, fmt.Errorf(`executable "aider" not found in PATH`))
+		}
+	case AgentKindSim:
+		scenario := viper.GetString(prefix + ".scenario")
+		if scenario == "" {
+			errs = append(errs, fmt.Errorf(`missing "%s.scenario"`, prefix))
+		} else if _, err := loadSimScenario(scenario); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		cmd := viper.GetString(prefix + ".cmd")
+		if cmd == "" && prefix != "agent" {
+			errs = append(errs, fmt.Errorf(`missing "%s.cmd"`, prefix))
+		}
+		if cmd != "" {
+			if err := checkAgentPlaceholders(cmd); err != nil {
+				errs = append(errs, err)
+			}
+			if _, err := exec.LookPath(strings.Fields(cmd)[0]); err != nil {
+				errs = append(errs, fmt.Errorf(
+					`executable "%s" not found in PATH`, strings.Fields(cmd)[0]))
+			}
+			if viper.GetString(prefix+".prompt_via") == "file" &&
+				!strings.Contains(cmd, "{prompt_file}") {
+				errs = append(errs, fmt.Errorf(
+					`prompt_via is "file", but "%s.cmd" has no "{prompt_file}" placeholder`,
+					prefix))
+			}
+		}
+	}
+
+	for _, err := range errs {
+		log.Errorf("%s: %s", name, err)
+	}
+	if len(errs) > 0 {
+		ReportGate("config/"+name, false, "%d problems", len(errs))
+		return false
+	}
+	ReportGate("config/"+name, true, "ok")
+	return true
+}
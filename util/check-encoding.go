@@ -0,0 +1,141 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// utf8BOM is the byte order mark some editors and agents prepend to
+// UTF-8 files, which breaks msgfmt.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// auditPoData scans the raw bytes of a po file for encoding problems:
+// non-UTF-8 bytes, an UTF-8 BOM, CRLF line endings, and a charset
+// header which does not declare UTF-8.
+func auditPoData(data []byte, fileName string) []error {
+	var errs []error
+
+	if bytes.HasPrefix(data, utf8BOM) {
+		errs = append(errs, fmt.Errorf(`"%s" starts with an UTF-8 BOM`, fileName))
+	}
+	if !utf8.Valid(data) {
+		errs = append(errs, fmt.Errorf(`"%s" contains non-UTF-8 bytes`, fileName))
+	}
+	if bytes.Contains(data, []byte("\r\n")) {
+		errs = append(errs, fmt.Errorf(`"%s" contains CRLF line endings`, fileName))
+	}
+	if poFile, err := ParsePoData(data, fileName); err == nil {
+		contentType := poFile.HeaderField("Content-Type")
+		if !strings.Contains(strings.ToLower(contentType), "charset=utf-8") {
+			errs = append(errs, fmt.Errorf(
+				`"%s" declares charset "%s", expect UTF-8`, fileName, contentType))
+		}
+	}
+	return errs
+}
+
+// normalizePoData fixes the mechanical encoding problems of a po file:
+// the BOM is stripped and CRLF line endings become LF. Non-UTF-8 bytes
+// cannot be repaired automatically.
+func normalizePoData(data []byte) []byte {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	return bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+}
+
+// auditPoFileInWorktree audits one po file of the working tree, and
+// normalizes it first if "--fix" is given.
+func auditPoFileInWorktree(fileName string) ([]error, bool) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return []error{err}, false
+	}
+	if FlagFixEncoding() {
+		if fixed := normalizePoData(data); !bytes.Equal(fixed, data) {
+			if err := ioutil.WriteFile(fileName, fixed, 0644); err != nil {
+				return []error{err}, false
+			}
+			log.Infof(`normalized encoding of "%s"`, fileName)
+			data = fixed
+		}
+	}
+	errs := auditPoData(data, fileName)
+	return errs, len(errs) == 0
+}
+
+// auditPoFilesInRange audits the po files changed by each commit of a
+// commit range, reading the blobs of the commits themselves.
+func auditPoFilesInRange(commitRange string) ([]error, bool) {
+	var errs []error
+
+	cmd := exec.Command("git", "rev-list", commitRange)
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		return []error{fmt.Errorf(`fail to list commits of "%s": %s`, commitRange, err)},
+			false
+	}
+	for _, commit := range strings.Fields(string(buf)) {
+		files, err := commitChangedFiles(commit)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, fileName := range files {
+			if filepath.Dir(fileName) != PoDir || filepath.Ext(fileName) != ".po" {
+				continue
+			}
+			show := exec.Command("git", "show", commit+":"+fileName)
+			show.Dir = GitRootDir
+			data, err := show.Output()
+			if err != nil {
+				continue
+			}
+			for _, err := range auditPoData(data,
+				fmt.Sprintf("%s@%s", fileName, AbbrevCommit(commit))) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs, len(errs) == 0
+}
+
+// CmdCheckEncoding implements check-encoding sub command.
+func CmdCheckEncoding(args ...string) bool {
+	if commitRange := FlagEncodingRange(); commitRange != "" {
+		errs, ok := auditPoFilesInRange(commitRange)
+		ReportGate("check-encoding/"+commitRange, ok, "%d messages", len(errs))
+		for _, err := range errs {
+			log.Error(err)
+		}
+		return ok
+	}
+
+	if len(args) == 0 {
+		args = listPoFiles()
+	}
+	if len(args) == 0 {
+		log.Errorf(`cannot find any ".po" files to check`)
+		return false
+	}
+	ret := true
+	for _, fileName := range args {
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		poFile := filepath.Join(PoDir, locale+".po")
+		errs, ok := auditPoFileInWorktree(poFile)
+		ReportGate("check-encoding/"+locale, ok, "%d messages", len(errs))
+		if !ok {
+			ret = false
+		}
+		for _, err := range errs {
+			log.Errorf("[%s]\t%s", poFile, err)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// poRevisionDatePattern matches the timestamp format gettext tools
+// write into the PO-Revision-Date field, such as
+// "2023-05-21 14:02+0800".
+var poRevisionDatePattern = regexp.MustCompile(
+	`^[0-9]{4}-[0-9]{2}-[0-9]{2} [0-9]{2}:[0-9]{2}[+-][0-9]{4}$`)
+
+// checkPoHeader validates the header of a po file against the policy
+// maintainers check by hand: required fields are filled in, the
+// Language field matches the file name, PO-Revision-Date is a real
+// timestamp, and the charset is UTF-8.
+func checkPoHeader(locale, fileName string) ([]error, bool) {
+	var errs []error
+
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}, false
+	}
+	for _, name := range []string{
+		"Project-Id-Version",
+		"Last-Translator",
+		"Language-Team",
+		"Plural-Forms",
+	} {
+		if poFile.HeaderField(name) == "" {
+			errs = append(errs, fmt.Errorf(
+				`header field "%s" is missing or empty`, name))
+		}
+	}
+	if translator := poFile.HeaderField("Last-Translator"); strings.Contains(
+		translator, "FULL NAME <EMAIL@ADDRESS>") {
+		errs = append(errs, fmt.Errorf(
+			`header field "Last-Translator" still has the template value`))
+	}
+	if lang := poFile.HeaderField("Language"); lang != locale {
+		errs = append(errs, fmt.Errorf(
+			`header field "Language" is "%s", but should be "%s"`, lang, locale))
+	}
+	if date := poFile.HeaderField("PO-Revision-Date"); !poRevisionDatePattern.MatchString(date) {
+		errs = append(errs, fmt.Errorf(
+			`header field "PO-Revision-Date" is "%s", not a timestamp like "2023-05-21 14:02+0800"`,
+			date))
+	}
+	if contentType := poFile.HeaderField("Content-Type"); !strings.Contains(
+		strings.ToLower(contentType), "charset=utf-8") {
+		errs = append(errs, fmt.Errorf(
+			`header field "Content-Type" is "%s", but charset must be UTF-8`,
+			contentType))
+	}
+	if len(errs) > 0 {
+		return errs, false
+	}
+	return errs, true
+}
+
+// fixPoHeader repairs the common header issues of a po file in place:
+// the Language field is set from the file name, PO-Revision-Date is
+// refreshed to the current time, and the charset is forced to UTF-8.
+// Fields only the translator can fill in, such as Last-Translator, are
+// left alone.
+func fixPoHeader(locale, fileName string) error {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return err
+	}
+	poFile.SetHeaderField("Language", locale)
+	poFile.SetHeaderField("PO-Revision-Date",
+		time.Now().Format("2006-01-02 15:04-0700"))
+	contentType := poFile.HeaderField("Content-Type")
+	if !strings.Contains(strings.ToLower(contentType), "charset=utf-8") {
+		poFile.SetHeaderField("Content-Type", "text/plain; charset=UTF-8")
+	}
+	return poFile.Save(fileName)
+}
+
+// CmdCheckHeader implements check-header sub command.
+func CmdCheckHeader(args ...string) bool {
+	var (
+		ret = true
+	)
+
+	if len(args) == 0 {
+		args = listPoFiles()
+	}
+	if len(args) == 0 {
+		log.Errorf(`cannot find any ".po" files to check`)
+		return false
+	}
+	for _, fileName := range args {
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		poFile := filepath.Join(PoDir, locale+".po")
+		prompt := fmt.Sprintf("[%s]", poFile)
+		if FlagFixHeader() {
+			if err := fixPoHeader(locale, poFile); err != nil {
+				log.Errorf("%s\tfail to fix header: %s", prompt, err)
+				ret = false
+				continue
+			}
+		}
+		errs, ok := checkPoHeader(locale, poFile)
+		ReportGate("check-header/"+locale, ok, "%d messages", len(errs))
+		if !ok {
+			ret = false
+		}
+		for _, err := range errs {
+			log.Errorf("%s\t%s", prompt, err)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,101 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	optionColumnPattern = regexp.MustCompile(`^(\s*)(--?[a-zA-Z0-9][a-zA-Z0-9-]*(?:=\S+|\[=\S+\])?)(\s{2,})`)
+)
+
+// leadingWhitespace returns the leading spaces and tabs of a line.
+func leadingWhitespace(line string) string {
+	idx := strings.IndexFunc(line, func(r rune) bool {
+		return r != ' ' && r != '\t'
+	})
+	if idx < 0 {
+		return line
+	}
+	return line[:idx]
+}
+
+// checkEntryAlignment checks that the leading whitespace, tab structure,
+// and option-column alignment of a help text block survive translation.
+func checkEntryAlignment(msgID, msgStr string) []error {
+	var errs []error
+
+	if msgStr == "" {
+		return nil
+	}
+	if !strings.ContainsAny(msgID, "\n\t") && !strings.HasPrefix(msgID, " ") {
+		return nil
+	}
+
+	idLines := strings.Split(msgID, "\n")
+	strLines := strings.Split(msgStr, "\n")
+
+	aligned := false
+	for _, line := range idLines {
+		if leadingWhitespace(line) != "" {
+			aligned = true
+			break
+		}
+	}
+	if !aligned {
+		return nil
+	}
+
+	if len(idLines) != len(strLines) {
+		errs = append(errs, fmt.Errorf(
+			"mismatched line count of aligned block: %d lines in msgid, but %d lines in msgstr\n"+
+				">> msgid: %s\n"+
+				">> msgstr: %s\n",
+			len(idLines), len(strLines), msgID, msgStr))
+		return errs
+	}
+
+	for i := range idLines {
+		expect := leadingWhitespace(idLines[i])
+		actual := leadingWhitespace(strLines[i])
+		if expect != actual {
+			errs = append(errs, fmt.Errorf(
+				"mismatched leading whitespace at line %d of msgstr\n"+
+					">> expect: %q\n"+
+					">> actual: %q\n",
+				i+1, idLines[i], strLines[i]))
+			continue
+		}
+		m := optionColumnPattern.FindStringSubmatch(idLines[i])
+		if m == nil {
+			continue
+		}
+		if !strings.HasPrefix(strLines[i], m[1]+m[2]) {
+			errs = append(errs, fmt.Errorf(
+				"mismatched option column at line %d of msgstr\n"+
+					">> expect: %q\n"+
+					">> actual: %q\n",
+				i+1, idLines[i], strLines[i]))
+		}
+	}
+	return errs
+}
+
+func checkAlignmentInPoFile(poFile string) ([]error, bool) {
+	if FlagIgnoreAlignment() {
+		return nil, true
+	}
+
+	entries, errs, ok := readMoEntries(poFile)
+	if !ok {
+		return errs, false
+	}
+	for _, entry := range entries {
+		errs = append(errs, checkEntryAlignment(entry.MsgID, entry.MsgStr)...)
+	}
+	if FlagReportAlignmentAsErrors() && len(errs) > 0 {
+		return errs, false
+	}
+	return errs, true
+}
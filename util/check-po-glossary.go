@@ -0,0 +1,112 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GlossaryDir is the directory which holds glossary files, one file
+// per locale, such as "po/glossary/zh_CN.txt".
+const GlossaryDir = "po/glossary"
+
+// GlossaryEntry defines a mandated translation for a term.
+type GlossaryEntry struct {
+	Term        string
+	Translation string
+}
+
+// LoadGlossary reads the glossary file for a locale. Each line of the
+// glossary file is a TAB separated pair of term and translation, and
+// lines starting with "#" are comments.
+func LoadGlossary(locale string) ([]GlossaryEntry, error) {
+	var entries []GlossaryEntry
+
+	fileName := filepath.Join(GlossaryDir, locale+".txt")
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 || strings.TrimSpace(fields[1]) == "" {
+			return nil, fmt.Errorf("invalid glossary entry at %s:%d: %s",
+				fileName, lineNo, line)
+		}
+		entries = append(entries, GlossaryEntry{
+			Term:        strings.TrimSpace(fields[0]),
+			Translation: strings.TrimSpace(fields[1]),
+		})
+	}
+	return entries, scanner.Err()
+}
+
+// checkEntryGlossary verifies that a translation uses the mandated
+// terms of the glossary.
+func checkEntryGlossary(glossary []GlossaryEntry, msgID, msgStr string) []error {
+	var errs []error
+
+	if msgStr == "" {
+		return nil
+	}
+	for _, entry := range glossary {
+		if !strings.Contains(strings.ToLower(msgID), strings.ToLower(entry.Term)) {
+			continue
+		}
+		if !strings.Contains(msgStr, entry.Translation) {
+			errs = append(errs, fmt.Errorf(
+				"glossary: term \"%s\" should be translated as \"%s\"\n"+
+					">> msgid: %s\n"+
+					">> msgstr: %s\n",
+				entry.Term, entry.Translation, msgID, msgStr))
+		}
+	}
+	return errs
+}
+
+func checkGlossaryInPoFile(locale, poFile string) ([]error, bool) {
+	var errs []error
+
+	if !FlagGlossary() {
+		return nil, true
+	}
+
+	glossary, err := LoadGlossary(locale)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, true
+		}
+		errs = append(errs, err)
+		return errs, false
+	}
+	if len(glossary) == 0 {
+		return nil, true
+	}
+	return checkGlossaryEntries(glossary, poFile)
+}
+
+// checkGlossaryEntries checks all entries of a po file against the
+// glossary.
+func checkGlossaryEntries(glossary []GlossaryEntry, poFile string) ([]error, bool) {
+	entries, errs, ok := readMoEntries(poFile)
+	if !ok {
+		return errs, false
+	}
+	for _, entry := range entries {
+		errs = append(errs, checkEntryGlossary(glossary, entry.MsgID, entry.MsgStr)...)
+	}
+	if len(errs) > 0 {
+		return errs, false
+	}
+	return errs, true
+}
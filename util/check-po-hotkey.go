@@ -0,0 +1,145 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// hotkeyPattern matches a mnemonic keyboard accelerator, such as
+	// "&Yes". "&&" is a literal ampersand.
+	hotkeyPattern = regexp.MustCompile(`&[a-zA-Z0-9]`)
+
+	// invisibleRunes are invisible or bidirectional control characters
+	// which have no business in a translation, and which often sneak
+	// in when text is copied from rich-text editors or chat windows.
+	invisibleRunes = map[rune]string{
+		'\u00ad': "SOFT HYPHEN",
+		'\u200b': "ZERO WIDTH SPACE",
+		'\u200c': "ZERO WIDTH NON-JOINER",
+		'\u200d': "ZERO WIDTH JOINER",
+		'\u200e': "LEFT-TO-RIGHT MARK",
+		'\u200f': "RIGHT-TO-LEFT MARK",
+		'\u202a': "LEFT-TO-RIGHT EMBEDDING",
+		'\u202b': "RIGHT-TO-LEFT EMBEDDING",
+		'\u202c': "POP DIRECTIONAL FORMATTING",
+		'\u202d': "LEFT-TO-RIGHT OVERRIDE",
+		'\u202e': "RIGHT-TO-LEFT OVERRIDE",
+		'\u2060': "WORD JOINER",
+		'\ufeff': "ZERO WIDTH NO-BREAK SPACE",
+	}
+
+	// fullWidthPunct maps full-width punctuation characters to their
+	// half-width counterparts. Mixing both widths of the same mark in
+	// one translation is almost always an oversight.
+	fullWidthPunct = map[rune]rune{
+		'，': ',',
+		'；': ';',
+		'：': ':',
+		'！': '!',
+		'？': '?',
+		'（': '(',
+		'）': ')',
+	}
+)
+
+// hotkeys returns the mnemonic accelerators of a message, with literal
+// "&&" sequences removed first.
+func hotkeys(text string) []string {
+	return hotkeyPattern.FindAllString(
+		strings.ReplaceAll(text, "&&", ""), -1)
+}
+
+// checkEntryHotkey checks one msgid/msgstr pair for lost or duplicated
+// accelerators, invisible control characters, and mixed punctuation
+// widths. It returns one explanation per finding. Mixed punctuation
+// widths are only warnings, because CJK translations legitimately keep
+// half-width marks inside quoted literals and code samples.
+func checkEntryHotkey(msgID, msgStr string) (problems, warnings []string) {
+	if nr := len(hotkeys(msgID)); nr > 0 {
+		if got := len(hotkeys(msgStr)); got != nr {
+			problems = append(problems, fmt.Sprintf(
+				"msgid has %d keyboard accelerators, msgstr has %d", nr, got))
+		}
+	}
+	for _, c := range msgStr {
+		if name, ok := invisibleRunes[c]; ok && !strings.ContainsRune(msgID, c) {
+			problems = append(problems, fmt.Sprintf(
+				"invisible character U+%04X (%s) in msgstr", c, name))
+		}
+	}
+	for full, half := range fullWidthPunct {
+		if strings.ContainsRune(msgStr, full) &&
+			strings.ContainsRune(msgStr, half) &&
+			!strings.ContainsRune(msgID, full) {
+			warnings = append(warnings, fmt.Sprintf(
+				"mixed full-width \"%c\" and half-width \"%c\" punctuation in msgstr",
+				full, half))
+		}
+	}
+	return problems, warnings
+}
+
+// checkHotkeysInPoFile checks accelerators and suspicious characters of
+// all translated entries in a po file. Mixed punctuation widths only
+// fail the check with option "--report-punctuation-as-errors".
+func checkHotkeysInPoFile(fileName string) ([]error, bool) {
+	var (
+		errs      []error
+		nrProblem int
+	)
+
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}, false
+	}
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || !entry.IsTranslated() || entry.IsFuzzy() {
+			continue
+		}
+		for _, pair := range placeholderPairs(entry) {
+			problems, warnings := checkEntryHotkey(pair[0], pair[1])
+			nrProblem += len(problems)
+			for _, problem := range append(problems, warnings...) {
+				errs = append(errs, fmt.Errorf(
+					"hotkey: %s\n"+
+						">> msgid: %s\n"+
+						">> msgstr: %s\n",
+					problem, pair[0], pair[1]))
+			}
+		}
+	}
+	if nrProblem > 0 || (FlagReportPunctuationAsErrors() && len(errs) > 0) {
+		return errs, false
+	}
+	return errs, true
+}
+
+// DetectHotkeyIssues checks accelerators and suspicious characters of
+// all translated entries of a po file, and reports problems as review
+// issues.
+func DetectHotkeyIssues(fileName string) ([]ReviewIssue, error) {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var issues []ReviewIssue
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || !entry.IsTranslated() || entry.IsFuzzy() {
+			continue
+		}
+		for _, pair := range placeholderPairs(entry) {
+			problems, warnings := checkEntryHotkey(pair[0], pair[1])
+			for _, problem := range append(problems, warnings...) {
+				issues = append(issues, ReviewIssue{
+					MsgID:       pair[0],
+					MsgStr:      pair[1],
+					Explanation: fmt.Sprintf("hotkey: %s", problem),
+					Severity:    "warning",
+				})
+			}
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,211 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// formatSpecPattern matches C printf format specifiers, including
+	// positional arguments ("%1$s") and length modifiers ("%lu").
+	formatSpecPattern = regexp.MustCompile(
+		`%(?:[0-9]+\$)?[-+ #0']*(?:[0-9]+|\*)?(?:\.(?:[0-9]+|\*))?` +
+			`(?:hh|h|ll|l|L|q|j|z|t)?[diouxXeEfFgGaAcsp]|%%`)
+
+	// gitPlaceholderPattern matches placeholders specific to Git
+	// messages: ref-filter atoms like "%(refname)" and argument
+	// placeholders like "<file>" in usage strings.
+	gitPlaceholderPattern = regexp.MustCompile(
+		`%\([a-zA-Z][a-zA-Z0-9_:*-]*\)|<[a-z][a-z0-9_-]*>`)
+
+	// positionalSpecPattern matches positional format specifiers,
+	// which allow a translation to reorder its arguments.
+	positionalSpecPattern = regexp.MustCompile(`%[0-9]+\$`)
+)
+
+// formatSpecs returns the C format specifiers of a message in order of
+// appearance. "%%" is a literal percent sign and is ignored.
+func formatSpecs(text string) []string {
+	var specs []string
+	for _, spec := range formatSpecPattern.FindAllString(text, -1) {
+		if spec == "%%" {
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// sameSpecs checks whether two lists of format specifiers are
+// identical, ignoring order if reordered is true.
+func sameSpecs(a, b []string, reordered bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if reordered {
+		counts := make(map[string]int)
+		for _, spec := range a {
+			counts[normalizeSpec(spec)]++
+		}
+		for _, spec := range b {
+			counts[normalizeSpec(spec)]--
+		}
+		for _, count := range counts {
+			if count != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSpec strips the positional prefix of a format specifier, so
+// "%1$s" compares equal to "%s".
+func normalizeSpec(spec string) string {
+	return positionalSpecPattern.ReplaceAllString(spec, "%")
+}
+
+// checkEntryPlaceholders checks one msgid/msgstr pair for mismatched
+// format specifiers, lost Git placeholders, and inconsistent leading or
+// trailing whitespace. It returns one explanation per problem. Usage
+// placeholders like "<file>" are reported as warnings, because
+// translations legitimately translate the placeholder names.
+func checkEntryPlaceholders(msgID, msgStr string) (problems, warnings []string) {
+	oldSpecs := formatSpecs(msgID)
+	newSpecs := formatSpecs(msgStr)
+	if !sameSpecs(oldSpecs, newSpecs, positionalSpecPattern.MatchString(msgStr)) {
+		problems = append(problems, fmt.Sprintf(
+			"mismatched format specifiers: msgid has [%s], msgstr has [%s]",
+			strings.Join(oldSpecs, " "), strings.Join(newSpecs, " ")))
+	}
+	for _, placeholder := range gitPlaceholderPattern.FindAllString(msgID, -1) {
+		if strings.Contains(msgStr, placeholder) {
+			continue
+		}
+		message := fmt.Sprintf(
+			"placeholder \"%s\" of msgid not found in msgstr", placeholder)
+		if strings.HasPrefix(placeholder, "%(") {
+			// Ref-filter atoms are literal syntax and must survive
+			// translation verbatim.
+			problems = append(problems, message)
+		} else {
+			warnings = append(warnings, message)
+		}
+	}
+	if strings.HasSuffix(msgID, "\n") != strings.HasSuffix(msgStr, "\n") {
+		problems = append(problems,
+			"msgid and msgstr do not both end with a newline")
+	}
+	if leadingWhitespace(msgID) != leadingWhitespace(msgStr) {
+		problems = append(problems, fmt.Sprintf(
+			"leading whitespace changed from %q to %q",
+			leadingWhitespace(msgID), leadingWhitespace(msgStr)))
+	}
+	if trailingWhitespace(msgID) != trailingWhitespace(msgStr) {
+		problems = append(problems, fmt.Sprintf(
+			"trailing whitespace changed from %q to %q",
+			trailingWhitespace(msgID), trailingWhitespace(msgStr)))
+	}
+	return problems, warnings
+}
+
+// trailingWhitespace returns the spaces and tabs a message ends with.
+func trailingWhitespace(text string) string {
+	return text[len(strings.TrimRight(text, " \t")):]
+}
+
+// placeholderPairs returns the msgid/msgstr pairs of an entry to check,
+// expanding plural forms. The first plural form translates msgid, the
+// other forms translate msgid_plural.
+func placeholderPairs(entry *PoEntry) [][2]string {
+	if len(entry.MsgStrPlural) == 0 {
+		return [][2]string{{entry.MsgID, entry.MsgStr}}
+	}
+	var pairs [][2]string
+	for i, msgStr := range entry.MsgStrPlural {
+		if i == 0 {
+			pairs = append(pairs, [2]string{entry.MsgID, msgStr})
+		} else {
+			pairs = append(pairs, [2]string{entry.MsgIDPlural, msgStr})
+		}
+	}
+	return pairs
+}
+
+// checkPlaceholdersInPoFile checks format specifiers and placeholders
+// of all translated entries in a po file. Lost usage placeholders only
+// fail the check with option "--report-placeholders-as-errors".
+func checkPlaceholdersInPoFile(fileName string) ([]error, bool) {
+	var (
+		errs      []error
+		nrProblem int
+	)
+
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}, false
+	}
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || !entry.IsTranslated() || entry.IsFuzzy() {
+			continue
+		}
+		for _, pair := range placeholderPairs(entry) {
+			problems, warnings := checkEntryPlaceholders(pair[0], pair[1])
+			nrProblem += len(problems)
+			for _, problem := range append(problems, warnings...) {
+				errs = append(errs, fmt.Errorf(
+					"placeholder: %s\n"+
+						">> msgid: %s\n"+
+						">> msgstr: %s\n",
+					problem, pair[0], pair[1]))
+			}
+		}
+	}
+	if nrProblem > 0 || (FlagReportPlaceholdersAsErrors() && len(errs) > 0) {
+		return errs, false
+	}
+	return errs, true
+}
+
+// DetectPlaceholderIssues checks format specifiers and placeholders of
+// all translated entries of a po file, and reports problems as review
+// issues.
+func DetectPlaceholderIssues(fileName string) ([]ReviewIssue, error) {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var issues []ReviewIssue
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || !entry.IsTranslated() || entry.IsFuzzy() {
+			continue
+		}
+		for _, pair := range placeholderPairs(entry) {
+			problems, warnings := checkEntryPlaceholders(pair[0], pair[1])
+			for _, problem := range problems {
+				issues = append(issues, ReviewIssue{
+					MsgID:       pair[0],
+					MsgStr:      pair[1],
+					Explanation: fmt.Sprintf("placeholder: %s", problem),
+					Severity:    "error",
+				})
+			}
+			for _, warning := range warnings {
+				issues = append(issues, ReviewIssue{
+					MsgID:       pair[0],
+					MsgStr:      pair[1],
+					Explanation: fmt.Sprintf("placeholder: %s", warning),
+					Severity:    "warning",
+				})
+			}
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// canonicalPluralForms maps language codes to the canonical
+// Plural-Forms expression translators are expected to use. Languages
+// not listed here are only checked for internal consistency.
+var canonicalPluralForms = map[string]string{
+	"bg":    "nplurals=2; plural=(n != 1);",
+	"ca":    "nplurals=2; plural=(n != 1);",
+	"de":    "nplurals=2; plural=(n != 1);",
+	"el":    "nplurals=2; plural=(n != 1);",
+	"es":    "nplurals=2; plural=(n != 1);",
+	"fr":    "nplurals=2; plural=(n > 1);",
+	"id":    "nplurals=1; plural=0;",
+	"it":    "nplurals=2; plural=(n != 1);",
+	"ja":    "nplurals=1; plural=0;",
+	"ko":    "nplurals=1; plural=0;",
+	"pl":    "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"pt_PT": "nplurals=2; plural=(n != 1);",
+	"ru":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"sv":    "nplurals=2; plural=(n != 1);",
+	"tr":    "nplurals=2; plural=(n != 1);",
+	"uk":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"vi":    "nplurals=1; plural=0;",
+	"zh_CN": "nplurals=2; plural=(n != 1);",
+	"zh_TW": "nplurals=2; plural=(n != 1);",
+}
+
+// npluralsPattern extracts the nplurals count of a Plural-Forms
+// expression.
+var npluralsPattern = regexp.MustCompile(`nplurals=([0-9]+)`)
+
+// normalizePluralForms strips whitespace of a Plural-Forms expression,
+// so cosmetic differences do not count as mismatches.
+func normalizePluralForms(pluralForms string) string {
+	return strings.Join(strings.Fields(pluralForms), "")
+}
+
+// checkPluralFormsInPoFile validates the Plural-Forms header of a po
+// file against the canonical expression of the language, and verifies
+// every translated plural entry has exactly nplurals msgstr[n] forms.
+// A non-canonical expression is only a warning, because translation
+// teams occasionally pick a different but working expression. A wrong
+// msgstr[n] count is always an error.
+func checkPluralFormsInPoFile(locale, fileName string) ([]error, bool) {
+	var (
+		errs      []error
+		nrProblem int
+	)
+
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}, false
+	}
+	pluralForms := poFile.HeaderField("Plural-Forms")
+	if pluralForms == "" {
+		errs = append(errs, fmt.Errorf(
+			`header field "Plural-Forms" is missing or empty`))
+		return errs, false
+	}
+	if canonical, ok := canonicalPluralForms[locale]; ok {
+		if normalizePluralForms(pluralForms) != normalizePluralForms(canonical) {
+			errs = append(errs, fmt.Errorf(
+				`header field "Plural-Forms" is "%s", but "%s" usually uses "%s"`,
+				pluralForms, locale, canonical))
+		}
+	}
+	m := npluralsPattern.FindStringSubmatch(pluralForms)
+	if m == nil {
+		errs = append(errs, fmt.Errorf(
+			`cannot find "nplurals=N" in Plural-Forms header "%s"`, pluralForms))
+		return errs, false
+	}
+	nplurals, _ := strconv.Atoi(m[1])
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || !entry.hasPlural || !entry.IsTranslated() || entry.IsFuzzy() {
+			continue
+		}
+		if len(entry.MsgStrPlural) != nplurals {
+			nrProblem++
+			errs = append(errs, fmt.Errorf(
+				"plural entry has %d msgstr[n] forms, but nplurals=%d\n"+
+					">> msgid: %s\n",
+				len(entry.MsgStrPlural), nplurals, entry.MsgID))
+		}
+	}
+	if nrProblem > 0 {
+		return errs, false
+	}
+	return errs, true
+}
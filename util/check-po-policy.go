@@ -0,0 +1,52 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// policyActive indicates whether any explicit po workflow policy is
+// enabled, either from the command line or from the config file.
+func policyActive() bool {
+	return FlagNoFuzzy() || FlagNoObsolete() || len(FlagRequireHeaderFields()) > 0
+}
+
+// checkPolicyInPoFile enforces explicit policies of the git po
+// workflow: no fuzzy entries, no obsolete ("#~") entries, and required
+// header fields. Each violating entry gets its own diagnostic, instead
+// of relying on what msgfmt happens to reject.
+func checkPolicyInPoFile(fileName string) ([]error, bool) {
+	var errs []error
+
+	if !policyActive() {
+		return nil, true
+	}
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}, false
+	}
+	for _, entry := range poFile.Entries {
+		if FlagNoFuzzy() && entry.IsFuzzy() && !entry.Obsolete {
+			errs = append(errs, fmt.Errorf(
+				"policy: fuzzy entry is not allowed\n"+
+					">> msgid: %s\n",
+				entry.MsgID))
+		}
+		if FlagNoObsolete() && entry.Obsolete {
+			errs = append(errs, fmt.Errorf(
+				"policy: obsolete entry (#~) should be removed\n"+
+					">> msgid: %s\n",
+				entry.MsgID))
+		}
+	}
+	for _, field := range FlagRequireHeaderFields() {
+		if strings.TrimSpace(poFile.HeaderField(field)) == "" {
+			errs = append(errs, fmt.Errorf(
+				`policy: required header field "%s" is missing or empty`, field))
+		}
+	}
+	if len(errs) > 0 {
+		return errs, false
+	}
+	return errs, true
+}
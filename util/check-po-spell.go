@@ -0,0 +1,150 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// builtinMisspellings maps frequent misspellings to their corrections,
+// used as a fallback when neither hunspell nor aspell is installed.
+var builtinMisspellings = map[string]string{
+	"acommit":    "a commit",
+	"commited":   "committed",
+	"enviroment": "environment",
+	"existant":   "existent",
+	"recieve":    "receive",
+	"refered":    "referred",
+	"repositry":  "repository",
+	"seperate":   "separate",
+	"succesful":  "successful",
+	"teh":        "the",
+	"untill":     "until",
+	"wich":       "which",
+}
+
+// loadSpellIgnoreList reads the per-repo ignore list for a locale from
+// "po/.spellignore-XX", one word per line, "#" for comments.
+func loadSpellIgnoreList(locale string) map[string]bool {
+	ignore := make(map[string]bool)
+	f, err := os.Open(filepath.Join(PoDir, ".spellignore-"+locale))
+	if err != nil {
+		return ignore
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		ignore[strings.ToLower(word)] = true
+	}
+	return ignore
+}
+
+// stripPlaceholders removes format specifiers and placeholders from a
+// message, so a spellchecker does not stumble over them.
+func stripPlaceholders(text string) string {
+	text = formatSpecPattern.ReplaceAllString(text, " ")
+	text = gitPlaceholderPattern.ReplaceAllString(text, " ")
+	return text
+}
+
+// spellCheckCommand returns the external spellchecker command for a
+// locale, or nil if neither hunspell nor aspell is installed.
+func spellCheckCommand(locale string) *exec.Cmd {
+	if _, err := exec.LookPath("hunspell"); err == nil {
+		return exec.Command("hunspell", "-l", "-d", locale)
+	}
+	if _, err := exec.LookPath("aspell"); err == nil {
+		return exec.Command("aspell", "list", "-l", locale)
+	}
+	return nil
+}
+
+// spellCheckText returns the probable misspellings in a text. It pipes
+// the text through hunspell or aspell if installed, and falls back to
+// the builtin list of frequent misspellings otherwise.
+func spellCheckText(locale, text string) ([]string, error) {
+	cmd := spellCheckCommand(locale)
+	if cmd == nil {
+		var words []string
+		for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !('a' <= r && r <= 'z') && r != '\''
+		}) {
+			if fix, ok := builtinMisspellings[word]; ok {
+				words = append(words, fmt.Sprintf("%s (did you mean %q?)", word, fix))
+			}
+		}
+		return words, nil
+	}
+	cmd.Stdin = strings.NewReader(text)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("fail to run %s: %s", cmd.Args[0], err)
+	}
+	var words []string
+	for _, word := range strings.Split(out.String(), "\n") {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words, nil
+}
+
+// checkSpellInPoFile spellchecks the translations of a po file. The
+// check is off by default, and is turned on with "check-po --spell".
+func checkSpellInPoFile(locale, poFile string) ([]error, bool) {
+	if !FlagSpell() {
+		return nil, true
+	}
+
+	file, err := LoadPoFile(poFile)
+	if err != nil {
+		return []error{err}, false
+	}
+	ignore := loadSpellIgnoreList(locale)
+	var text strings.Builder
+	for _, entry := range file.Entries {
+		if entry.Obsolete || entry.MsgID == "" || !entry.IsTranslated() {
+			continue
+		}
+		text.WriteString(stripPlaceholders(entry.MsgStr))
+		text.WriteString("\n")
+		for _, msgStr := range entry.MsgStrPlural {
+			text.WriteString(stripPlaceholders(msgStr))
+			text.WriteString("\n")
+		}
+	}
+	words, err := spellCheckText(locale, text.String())
+	if err != nil {
+		log.Warnf(`spellcheck of "%s" skipped: %s`, poFile, err)
+		return nil, true
+	}
+	seen := make(map[string]bool)
+	var errs []error
+	for _, word := range words {
+		key := strings.ToLower(word)
+		if seen[key] || ignore[strings.ToLower(strings.Fields(word)[0])] {
+			continue
+		}
+		seen[key] = true
+		errs = append(errs, fmt.Errorf("probable typo: %s", word))
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+	if FlagReportTyposAsErrors() && len(errs) > 0 {
+		return errs, false
+	}
+	return errs, true
+}
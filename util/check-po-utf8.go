@@ -0,0 +1,91 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkEntryUTF8 scans one translated string for corrupted multi-byte
+// text: invalid UTF-8 sequences, replacement characters, and common
+// mojibake patterns left behind when UTF-8 is truncated by byte offset
+// or decoded twice.
+func checkEntryUTF8(msgStr string) []string {
+	var problems []string
+
+	if !utf8.ValidString(msgStr) {
+		problems = append(problems, "invalid UTF-8 sequence")
+	}
+	if strings.ContainsRune(msgStr, '�') {
+		problems = append(problems,
+			"contains the Unicode replacement character U+FFFD")
+	}
+	if looksLikeMojibake(msgStr) {
+		problems = append(problems,
+			"looks like mojibake (UTF-8 decoded as a single-byte charset)")
+	}
+	return problems
+}
+
+// looksLikeMojibake reports the tell-tale rune pairs of UTF-8 text
+// which was decoded as Latin-1 and re-encoded, such as "Ã©" for "é".
+func looksLikeMojibake(text string) bool {
+	runes := []rune(text)
+	for i := 0; i+1 < len(runes); i++ {
+		switch runes[i] {
+		case 'Â', 'Ã':
+			// A Latin-1 continuation byte decoded as its own rune.
+			if runes[i+1] >= 0x80 && runes[i+1] <= 0xBF {
+				return true
+			}
+		case 'â':
+			// Misdecoded UTF-8 punctuation, such as curly quotes.
+			if runes[i+1] == '€' || runes[i+1] == '‚' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// utf8Validator scans the entries an agent changed for corrupted
+// multi-byte sequences, so broken text fails the run before it can be
+// committed.
+type utf8Validator struct{}
+
+func (v *utf8Validator) Name() string {
+	return "utf8"
+}
+
+func (v *utf8Validator) Validate(old *PoFile, fileName string) []error {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}
+	}
+	oldEntries := make(map[string]string)
+	if old != nil {
+		for _, entry := range old.Entries {
+			oldEntries[entry.Key()] = entry.MsgStr +
+				strings.Join(entry.MsgStrPlural, "\x00")
+		}
+	}
+	var errs []error
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" {
+			continue
+		}
+		msgStr := entry.MsgStr + strings.Join(entry.MsgStrPlural, "\x00")
+		if old != nil && oldEntries[entry.Key()] == msgStr {
+			// Only check entries the agent changed.
+			continue
+		}
+		for _, problem := range checkEntryUTF8(msgStr) {
+			errs = append(errs, fmt.Errorf(
+				"%s\n"+
+					">> msgid: %s\n"+
+					">> msgstr: %s\n",
+				problem, entry.MsgID, entry.MsgStr))
+		}
+	}
+	return errs
+}
@@ -16,20 +16,26 @@ func CheckPoFile(locale, poFile string) bool {
 
 // CheckPoFileWithPrompt checks syntax of "po/xx.po", and use specific prompt.
 func CheckPoFileWithPrompt(locale, poFile string, prompt string) bool {
-	var (
-		ret  = true
-		errs []error
-	)
 	locale = strings.TrimSuffix(filepath.Base(locale), ".po")
 	_, err := GetPrettyLocaleName(locale)
 	if err != nil {
 		log.Error(err)
-		ret = false
-		return ret
+		return false
 	}
 	if prompt == "" {
 		prompt = fmt.Sprintf("[%s]", filepath.Join(PoDir, locale+".po"))
 	}
+	return checkPoFile(locale, poFile, prompt, false)
+}
+
+// checkPoFile runs the po file checks shared by the worktree and the
+// stdin path. Checks which need a real locale, such as plural forms,
+// glossary and spellcheck, are skipped if skipLocaleChecks is set.
+func checkPoFile(locale, poFile, prompt string, skipLocaleChecks bool) bool {
+	var (
+		ret  = true
+		errs []error
+	)
 
 	if !Exist(poFile) {
 		log.Errorf(`%s\tfail to check "%s", does not exist`, prompt, poFile)
@@ -39,6 +45,7 @@ func CheckPoFileWithPrompt(locale, poFile string, prompt string) bool {
 
 	// Run msgfmt to check syntax of a .po file
 	errs, ret = checkPoSyntax(poFile)
+	ReportGate("check-po/"+locale+"/syntax", ret, "%d messages", len(errs))
 	for _, err := range errs {
 		if !ret {
 			log.Errorf("%s\t%s", prompt, err)
@@ -47,8 +54,105 @@ func CheckPoFileWithPrompt(locale, poFile string, prompt string) bool {
 		}
 	}
 
+	// Enforce explicit workflow policies in a .po file.
+	errs, policyOK := checkPolicyInPoFile(poFile)
+	ReportGate("check-po/"+locale+"/policy", policyOK, "%d messages", len(errs))
+	if !policyOK {
+		ret = policyOK
+	}
+	for _, err := range errs {
+		log.Errorf("%s\t%s", prompt, err)
+	}
+
+	// Check format specifiers and placeholders in a .po file.
+	errs, placeholderOK := checkPlaceholdersInPoFile(poFile)
+	ReportGate("check-po/"+locale+"/placeholder", placeholderOK, "%d messages", len(errs))
+	if !placeholderOK {
+		ret = placeholderOK
+	}
+	for _, err := range errs {
+		if !placeholderOK {
+			log.Errorf("%s\t%s", prompt, err)
+		} else {
+			log.Warnf("%s\t%s", prompt, err)
+		}
+	}
+
+	// Check plural forms of the header and plural entries in a .po file.
+	if !skipLocaleChecks {
+		errs, pluralOK := checkPluralFormsInPoFile(locale, poFile)
+		ReportGate("check-po/"+locale+"/plural", pluralOK, "%d messages", len(errs))
+		if !pluralOK {
+			ret = pluralOK
+		}
+		for _, err := range errs {
+			if !pluralOK {
+				log.Errorf("%s\t%s", prompt, err)
+			} else {
+				log.Warnf("%s\t%s", prompt, err)
+			}
+		}
+	}
+
+	// Check keyboard accelerators and suspicious characters in a .po file.
+	errs, hotkeyOK := checkHotkeysInPoFile(poFile)
+	ReportGate("check-po/"+locale+"/hotkey", hotkeyOK, "%d messages", len(errs))
+	if !hotkeyOK {
+		ret = hotkeyOK
+	}
+	for _, err := range errs {
+		if !hotkeyOK {
+			log.Errorf("%s\t%s", prompt, err)
+		} else {
+			log.Warnf("%s\t%s", prompt, err)
+		}
+	}
+
+	// Check alignment of help text blocks in a .po file.
+	errs, alignmentOK := checkAlignmentInPoFile(poFile)
+	ReportGate("check-po/"+locale+"/alignment", alignmentOK, "%d messages", len(errs))
+	if !alignmentOK {
+		ret = alignmentOK
+	}
+	for _, err := range errs {
+		if !alignmentOK {
+			log.Errorf("%s\t%s", prompt, err)
+		} else {
+			log.Warnf("%s\t%s", prompt, err)
+		}
+	}
+
+	// Check glossary terms in a .po file.
+	if !skipLocaleChecks {
+		errs, glossaryOK := checkGlossaryInPoFile(locale, poFile)
+		ReportGate("check-po/"+locale+"/glossary", glossaryOK, "%d messages", len(errs))
+		if !glossaryOK {
+			ret = glossaryOK
+		}
+		for _, err := range errs {
+			log.Errorf("%s\t%s", prompt, err)
+		}
+	}
+
+	// Spellcheck translations in a .po file.
+	if !skipLocaleChecks {
+		errs, spellOK := checkSpellInPoFile(locale, poFile)
+		ReportGate("check-po/"+locale+"/spell", spellOK, "%d messages", len(errs))
+		if !spellOK {
+			ret = spellOK
+		}
+		for _, err := range errs {
+			if !spellOK {
+				log.Errorf("%s\t%s", prompt, err)
+			} else {
+				log.Warnf("%s\t%s", prompt, err)
+			}
+		}
+	}
+
 	// Check possible typos in a .po file.
 	errs, typosOK := checkTyposInPoFile(poFile)
+	ReportGate("check-po/"+locale+"/typos", typosOK, "%d messages", len(errs))
 	if !typosOK {
 		ret = typosOK
 	}
@@ -71,6 +175,21 @@ func CheckPoFileWithPrompt(locale, poFile string, prompt string) bool {
 	return ret
 }
 
+// CheckPoFileFromStdin checks a po file read from stdin. The locale is
+// unknown, so locale specific checks such as glossary are skipped.
+func CheckPoFileFromStdin() bool {
+	prompt := "[-]"
+
+	tmpFile, err := CopyStdinToTempFile("pofile")
+	if err != nil {
+		log.Errorf("%s\tfail to read stdin: %s", prompt, err)
+		return false
+	}
+	defer os.Remove(tmpFile)
+
+	return checkPoFile("-", tmpFile, prompt, true)
+}
+
 // CmdCheckPo implements check-po sub command.
 func CmdCheckPo(args ...string) bool {
 	var (
@@ -78,22 +197,7 @@ func CmdCheckPo(args ...string) bool {
 	)
 
 	if len(args) == 0 {
-		filepath.Walk("po", func(path string, info os.FileInfo, err error) error {
-			if info == nil {
-				return filepath.SkipDir
-			}
-			if !info.IsDir() {
-				if filepath.Ext(path) == ".po" {
-					args = append(args, path)
-				}
-				return nil
-			}
-			if path == "po" {
-				return nil
-			}
-			// skip subdir
-			return filepath.SkipDir
-		})
+		args = listPoFiles()
 	}
 
 	if len(args) == 0 {
@@ -101,12 +205,17 @@ func CmdCheckPo(args ...string) bool {
 		ret = false
 	}
 	for _, fileName := range args {
-		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
-		poFile := filepath.Join(PoDir, locale+".po")
+		if fileName == "-" {
+			if !CheckPoFileFromStdin() {
+				ret = false
+			}
+			continue
+		}
+		locale, poFile := resolvePoFile(fileName)
 		if !CheckPoFile(locale, poFile) {
 			ret = false
 		}
-		if FlagCore() {
+		if FlagCore() && !FlagNoRepo() {
 			if !CheckCorePoFile(locale) {
 				ret = false
 			}
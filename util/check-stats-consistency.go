@@ -0,0 +1,96 @@
+package util
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// commitStatsPattern matches translation statistics in l10n commit
+// subjects, such as "l10n: zh_CN: translate 120 new messages" or
+// "l10n: fr: translated 5381 messages".
+var commitStatsPattern = regexp.MustCompile(
+	`([a-zA-Z_]{2,}(?:\.[-a-zA-Z0-9_]+)?):\s+translated?\s+(\d+)\s+(?:new\s+)?messages`)
+
+// commitSubject returns the subject line of a commit.
+func commitSubject(commit string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%s", commit)
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// newlyTranslatedCount computes how many entries are translated in the
+// po file of a commit, but untranslated or missing in its parent.
+func newlyTranslatedCount(commit, fileName string) (int, error) {
+	newFile, err := loadPoFileOrBlob(commit + ":" + fileName)
+	if err != nil {
+		return 0, err
+	}
+	oldFile, err := loadPoFileOrBlob(commit + "^:" + fileName)
+	if err != nil {
+		// The po file or the parent commit does not exist, every
+		// translated entry counts as new.
+		oldFile = &PoFile{}
+	}
+	translated := make(map[string]bool)
+	for _, entry := range oldFile.Entries {
+		if entry.MsgID == "" || entry.Obsolete {
+			continue
+		}
+		if entry.IsTranslated() && !entry.IsFuzzy() {
+			translated[entry.Key()] = true
+		}
+	}
+	count := 0
+	for _, entry := range newFile.Entries {
+		if entry.MsgID == "" || entry.Obsolete {
+			continue
+		}
+		if entry.IsTranslated() && !entry.IsFuzzy() && !translated[entry.Key()] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// checkCommitStatsConsistency verifies the statistics in the subject
+// of an l10n commit against the actual entry delta of the po files
+// changed in the commit.
+func checkCommitStatsConsistency(commit string) bool {
+	subject, err := commitSubject(commit)
+	if err != nil {
+		log.Errorf("commit %s: fail to read commit subject: %s",
+			AbbrevCommit(commit), err)
+		return false
+	}
+	match := commitStatsPattern.FindStringSubmatch(subject)
+	if match == nil {
+		log.Warnf(`commit %s: no translation statistics found in subject "%s"`,
+			AbbrevCommit(commit), subject)
+		return true
+	}
+	locale := match[1]
+	claimed, _ := strconv.Atoi(match[2])
+	fileName := PoDir + "/" + locale + ".po"
+	actual, err := newlyTranslatedCount(commit, fileName)
+	if err != nil {
+		log.Errorf(`commit %s: fail to compute entry delta of "%s": %s`,
+			AbbrevCommit(commit), fileName, err)
+		return false
+	}
+	if claimed != actual {
+		log.Errorf(`commit %s: subject claims %d new messages for "%s", but %d entries are newly translated`,
+			AbbrevCommit(commit), claimed, locale, actual)
+		return false
+	}
+	log.Debugf(`commit %s: statistics of "%s" are consistent (%d new messages)`,
+		AbbrevCommit(commit), locale, actual)
+	return true
+}
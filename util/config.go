@@ -0,0 +1,128 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ConfigFileName is the name of the configuration file of git-po-helper,
+// searched in the root of the repository.
+const ConfigFileName = "git-po-helper"
+
+// SystemConfigFile is the system-level configuration file, read with
+// the lowest precedence.
+const SystemConfigFile = "/etc/git-po-helper.yaml"
+
+// configOrigins maps each config key to the file it was read from,
+// with higher precedence files overriding lower ones.
+var configOrigins = make(map[string]string)
+
+// configFiles returns the configuration files to load, lowest
+// precedence first: system, user, and project. An explicit "--config"
+// option replaces all of them.
+func configFiles() []string {
+	if fileName := FlagConfig(); fileName != "" {
+		return []string{fileName}
+	}
+	files := []string{SystemConfigFile}
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, "."+ConfigFileName+".yaml"))
+	}
+	rootDir := GitRootDir
+	if rootDir == "" {
+		rootDir = "."
+	}
+	files = append(files, filepath.Join(rootDir, ConfigFileName+".yaml"))
+	return files
+}
+
+// LoadConfig reads the configuration files in precedence order, and
+// turns on "GIT_PO_HELPER_*" environment variable overrides. Missing
+// files are not an error, except for a file given with "--config".
+func LoadConfig() {
+	viper.SetEnvPrefix("GIT_PO_HELPER")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	viper.AutomaticEnv()
+
+	for _, fileName := range configFiles() {
+		v := viper.New()
+		v.SetConfigFile(fileName)
+		v.SetConfigType("yaml")
+		if err := v.ReadInConfig(); err != nil {
+			if fileName == FlagConfig() {
+				log.Fatalf("fail to read config file: %s", err)
+			}
+			if Exist(fileName) {
+				log.Warnf("fail to read config file: %s", err)
+			}
+			continue
+		}
+		if err := viper.MergeConfigMap(v.AllSettings()); err != nil {
+			log.Warnf(`fail to merge config file "%s": %s`, fileName, err)
+			continue
+		}
+		for _, key := range v.AllKeys() {
+			configOrigins[key] = fileName
+		}
+		log.Debugf("loaded config file: %s", fileName)
+	}
+}
+
+// ConfigOrigin returns the file a config key was read from, or a note
+// that the value is a default or comes from the environment.
+func ConfigOrigin(key string) string {
+	if fileName, ok := configOrigins[key]; ok {
+		return fileName
+	}
+	envKey := "GIT_PO_HELPER_" + strings.ToUpper(
+		strings.NewReplacer(".", "_", "-", "_").Replace(key))
+	if viper.InConfig(key) {
+		return "(unknown)"
+	}
+	return "(default or $" + envKey + ")"
+}
+
+// CmdShowConfig implements show-config sub command, dumping the
+// effective configuration. With option "--origin", each value is
+// annotated with the file it came from.
+func CmdShowConfig() bool {
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		// Skip keys of bound command line flags, which are not
+		// configuration file variables.
+		if strings.Contains(key, "--") {
+			continue
+		}
+		if FlagShowConfigOrigin() {
+			fmt.Printf("%s = %v\t# %s\n", key, viper.Get(key), ConfigOrigin(key))
+		} else {
+			fmt.Printf("%s = %v\n", key, viper.Get(key))
+		}
+	}
+	return true
+}
+
+// AgentRetries returns config variable "agent.retries", the number of
+// times a failed agent invocation is retried.
+func AgentRetries() int {
+	return viper.GetInt("agent.retries")
+}
+
+// AgentRetryBackoff returns config variable "agent.retry_backoff", the
+// initial delay before retrying a failed agent invocation. The delay is
+// doubled after each attempt.
+func AgentRetryBackoff() time.Duration {
+	backoff := viper.GetDuration("agent.retry_backoff")
+	if backoff <= 0 {
+		backoff = 2 * time.Second
+	}
+	return backoff
+}
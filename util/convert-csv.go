@@ -0,0 +1,122 @@
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// csvHeader is the fixed header row of the tabular export, which
+// allows language teams to review translations in a spreadsheet and
+// round-trip the result safely.
+var csvHeader = []string{"msgctxt", "msgid", "msgstr", "status", "comment"}
+
+// entryStatus returns the review status of a po entry.
+func entryStatus(entry *PoEntry) string {
+	switch {
+	case entry.IsFuzzy():
+		return "fuzzy"
+	case entry.IsTranslated():
+		return "translated"
+	default:
+		return "untranslated"
+	}
+}
+
+// PoToCSV exports a parsed po file as tabular data. The separator is
+// ',' for CSV and '\t' for TSV.
+func PoToCSV(poFile *PoFile, sep rune) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	w.Comma = sep
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete {
+			continue
+		}
+		var comments []string
+		for _, comment := range entry.Comments {
+			if strings.HasPrefix(comment, "# ") {
+				comments = append(comments, strings.TrimPrefix(comment, "# "))
+			}
+		}
+		if err := w.Write([]string{
+			"",
+			entry.MsgID,
+			entry.MsgStr,
+			entryStatus(entry),
+			strings.Join(comments, "\n"),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// CSVToPo imports tabular data as a po file. The importer is strict:
+// the header row must match the export format, and each record must
+// have a valid status and a non-empty msgid.
+func CSVToPo(data []byte, sep rune) (*PoFile, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = sep
+	r.FieldsPerRecord = len(csvHeader)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse tabular data: %s", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("fail to parse tabular data: empty input")
+	}
+	for i, name := range csvHeader {
+		if records[0][i] != name {
+			return nil, fmt.Errorf(
+				`invalid header row: column %d is "%s", expect "%s"`,
+				i+1, records[0][i], name)
+		}
+	}
+	poFile := PoFile{
+		Header: &PoEntry{
+			MsgStr: "Content-Type: text/plain; charset=UTF-8\n",
+		},
+	}
+	for lineNo, record := range records[1:] {
+		msgID, msgStr, status, comment := record[1], record[2], record[3], record[4]
+		if msgID == "" {
+			return nil, fmt.Errorf("empty msgid in record %d", lineNo+2)
+		}
+		entry := PoEntry{
+			MsgID:  msgID,
+			MsgStr: msgStr,
+		}
+		for _, line := range strings.Split(comment, "\n") {
+			if line != "" {
+				entry.Comments = append(entry.Comments, "# "+line)
+			}
+		}
+		switch status {
+		case "fuzzy":
+			entry.Comments = append(entry.Comments, "#, fuzzy")
+		case "translated":
+			if msgStr == "" {
+				return nil, fmt.Errorf(
+					"record %d has status \"translated\" but empty msgstr", lineNo+2)
+			}
+		case "untranslated":
+			if msgStr != "" {
+				return nil, fmt.Errorf(
+					"record %d has status \"untranslated\" but non-empty msgstr", lineNo+2)
+			}
+		default:
+			return nil, fmt.Errorf(
+				`invalid status "%s" in record %d, expect "translated", "fuzzy" or "untranslated"`,
+				status, lineNo+2)
+		}
+		poFile.Entries = append(poFile.Entries, &entry)
+	}
+	return &poFile, nil
+}
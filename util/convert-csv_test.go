@@ -0,0 +1,127 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+var csvPoSample = `msgid ""
+msgstr ""
+"Project-Id-Version: git-po-helper\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "apple"
+msgstr "苹果"
+
+# keep the tone informal
+#, fuzzy
+msgid "cherry"
+msgstr "樱桃"
+
+msgid "date"
+msgstr ""
+
+#~ msgid "obsolete entry"
+#~ msgstr "过时条目"
+`
+
+func TestPoToCSV(t *testing.T) {
+	poFile, err := ParsePoData([]byte(csvPoSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	buf, err := PoToCSV(poFile, ',')
+	if err != nil {
+		t.Fatalf("fail to convert po to csv: %s", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+	if lines[0] != strings.Join(csvHeader, ",") {
+		t.Errorf("bad header row: %q", lines[0])
+	}
+	// The header row and three non-obsolete entries.
+	if len(lines) != 4 {
+		t.Fatalf("csv has %d rows, expect 4", len(lines))
+	}
+	if !strings.Contains(string(buf), ",cherry,樱桃,fuzzy,") {
+		t.Errorf("no fuzzy record in csv:\n%s", string(buf))
+	}
+	if strings.Contains(string(buf), "obsolete entry") {
+		t.Errorf("obsolete entry leaked into csv:\n%s", string(buf))
+	}
+}
+
+func TestCSVToPoRoundTrip(t *testing.T) {
+	poFile, err := ParsePoData([]byte(csvPoSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	for _, sep := range []rune{',', '\t'} {
+		buf, err := PoToCSV(poFile, sep)
+		if err != nil {
+			t.Fatalf("fail to convert po to csv: %s", err)
+		}
+		again, err := CSVToPo(buf, sep)
+		if err != nil {
+			t.Fatalf("fail to convert csv back to po: %s", err)
+		}
+		if len(again.Entries) != 3 {
+			t.Fatalf("round trip gives %d entries, expect 3", len(again.Entries))
+		}
+		if again.Entries[0].MsgID != "apple" || again.Entries[0].MsgStr != "苹果" {
+			t.Errorf("bad entry after round trip: %q -> %q",
+				again.Entries[0].MsgID, again.Entries[0].MsgStr)
+		}
+		if !again.Entries[1].IsFuzzy() {
+			t.Error("fuzzy status lost in round trip")
+		}
+		found := false
+		for _, comment := range again.Entries[1].Comments {
+			if comment == "# keep the tone informal" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("translator comment lost in round trip: %v",
+				again.Entries[1].Comments)
+		}
+		if again.Entries[2].IsTranslated() {
+			t.Error("untranslated entry reported as translated after round trip")
+		}
+	}
+}
+
+func TestCSVToPoStrictErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{
+			"bad header row",
+			"id,source,target\n",
+		},
+		{
+			"empty input",
+			"",
+		},
+		{
+			"empty msgid",
+			"msgctxt,msgid,msgstr,status,comment\n,,苹果,translated,\n",
+		},
+		{
+			"invalid status",
+			"msgctxt,msgid,msgstr,status,comment\n,apple,苹果,reviewed,\n",
+		},
+		{
+			"translated without msgstr",
+			"msgctxt,msgid,msgstr,status,comment\n,apple,,translated,\n",
+		},
+		{
+			"untranslated with msgstr",
+			"msgctxt,msgid,msgstr,status,comment\n,apple,苹果,untranslated,\n",
+		},
+	} {
+		if _, err := CSVToPo([]byte(tc.data), ','); err == nil {
+			t.Errorf("no error for %s", tc.name)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package util
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Structures for a minimal XLIFF 1.2 document, which is the exchange
+// format understood by most professional CAT tools.
+type xliffTarget struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+type xliffTransUnit struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source"`
+	Target xliffTarget `xml:"target"`
+	Notes  []string    `xml:"note,omitempty"`
+}
+
+type xliffBody struct {
+	Units []xliffTransUnit `xml:"trans-unit"`
+}
+
+type xliffFile struct {
+	Original       string    `xml:"original,attr"`
+	SourceLanguage string    `xml:"source-language,attr"`
+	TargetLanguage string    `xml:"target-language,attr"`
+	DataType       string    `xml:"datatype,attr"`
+	Body           xliffBody `xml:"body"`
+}
+
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+// PoToXliff converts a parsed po file into an XLIFF 1.2 document.
+// Entry states (translated, fuzzy, untranslated) and translator notes
+// are preserved.
+func PoToXliff(poFile *PoFile, locale string) ([]byte, error) {
+	doc := xliffDoc{
+		Version: "1.2",
+		Files: []xliffFile{
+			{
+				Original:       poFile.FileName,
+				SourceLanguage: "en",
+				TargetLanguage: locale,
+				DataType:       "plaintext",
+			},
+		},
+	}
+	body := &doc.Files[0].Body
+	unitNo := 0
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete {
+			continue
+		}
+		unitNo++
+		unit := xliffTransUnit{
+			ID:     fmt.Sprintf("%d", unitNo),
+			Source: entry.MsgID,
+			Target: xliffTarget{Text: entry.MsgStr},
+		}
+		switch {
+		case entry.IsFuzzy():
+			unit.Target.State = "needs-review-translation"
+		case entry.IsTranslated():
+			unit.Target.State = "translated"
+		default:
+			unit.Target.State = "new"
+		}
+		for _, comment := range entry.Comments {
+			if strings.HasPrefix(comment, "# ") {
+				unit.Notes = append(unit.Notes, strings.TrimPrefix(comment, "# "))
+			}
+		}
+		body.Units = append(body.Units, unit)
+	}
+	buf, err := xml.MarshalIndent(&doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(buf, '\n')...), nil
+}
+
+// XliffToPo converts an XLIFF 1.2 document back into a po file,
+// restoring entry states and translator notes.
+func XliffToPo(data []byte) (*PoFile, error) {
+	doc := xliffDoc{}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("fail to parse xliff: %s", err)
+	}
+	if len(doc.Files) == 0 {
+		return nil, fmt.Errorf("fail to parse xliff: no file element")
+	}
+	poFile := PoFile{
+		FileName: doc.Files[0].Original,
+		Header: &PoEntry{
+			MsgStr: fmt.Sprintf("Language: %s\nContent-Type: text/plain; charset=UTF-8\n",
+				doc.Files[0].TargetLanguage),
+		},
+	}
+	for _, file := range doc.Files {
+		for _, unit := range file.Body.Units {
+			entry := PoEntry{
+				MsgID:  unit.Source,
+				MsgStr: unit.Target.Text,
+			}
+			for _, note := range unit.Notes {
+				entry.Comments = append(entry.Comments, "# "+note)
+			}
+			if unit.Target.State == "needs-review-translation" {
+				entry.Comments = append(entry.Comments, "#, fuzzy")
+			}
+			poFile.Entries = append(poFile.Entries, &entry)
+		}
+	}
+	return &poFile, nil
+}
+
+// CmdConvert implements convert sub command.
+func CmdConvert(fileName string) bool {
+	var (
+		output = FlagConvertOutput()
+	)
+
+	switch {
+	case FlagConvertTo() != "":
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		poFile, err := LoadPoFile(fileName)
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		var buf []byte
+		switch FlagConvertTo() {
+		case "xliff":
+			buf, err = PoToXliff(poFile, locale)
+		case "csv":
+			buf, err = PoToCSV(poFile, ',')
+		case "tsv":
+			buf, err = PoToCSV(poFile, '\t')
+		default:
+			log.Errorf(`unknown format "%s", available formats: xliff, csv, tsv`,
+				FlagConvertTo())
+			return false
+		}
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		if err = WriteOutput(output, buf); err != nil {
+			log.Error(err)
+			return false
+		}
+	case FlagConvertFrom() != "":
+		buf, err := ReadInput(fileName)
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		var poFile *PoFile
+		switch FlagConvertFrom() {
+		case "xliff":
+			poFile, err = XliffToPo(buf)
+		case "csv":
+			poFile, err = CSVToPo(buf, ',')
+		case "tsv":
+			poFile, err = CSVToPo(buf, '\t')
+		default:
+			log.Errorf(`unknown format "%s", available formats: xliff, csv, tsv`,
+				FlagConvertFrom())
+			return false
+		}
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		// Run the same validation as check-po on the imported file.
+		tmpFile, err := ioutil.TempFile("", "pofile")
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		defer os.Remove(tmpFile.Name())
+		tmpFile.Close()
+		if err = poFile.Save(tmpFile.Name()); err != nil {
+			log.Error(err)
+			return false
+		}
+		if errs, ok := checkPoSyntax(tmpFile.Name()); !ok {
+			for _, err := range errs {
+				log.Error(err)
+			}
+			return false
+		}
+		if err = WriteOutput(output, poFile.Data()); err != nil {
+			log.Error(err)
+			return false
+		}
+	default:
+		log.Errorf(`please provide option "--to <format>" or "--from <format>"`)
+		return false
+	}
+	return true
+}
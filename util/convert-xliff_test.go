@@ -0,0 +1,73 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPoToXliff(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	buf, err := PoToXliff(poFile, "zh_CN")
+	if err != nil {
+		t.Fatalf("fail to convert po to xliff: %s", err)
+	}
+	xliff := string(buf)
+	if !strings.Contains(xliff, `target-language="zh_CN"`) {
+		t.Errorf("no target language in xliff:\n%s", xliff)
+	}
+	if !strings.Contains(xliff, "<source>could not open &#39;%s&#39;</source>") &&
+		!strings.Contains(xliff, "<source>could not open '%s'</source>") {
+		t.Errorf("no source element in xliff:\n%s", xliff)
+	}
+	if !strings.Contains(xliff, `state="needs-review-translation"`) {
+		t.Errorf("fuzzy entry not marked for review in xliff:\n%s", xliff)
+	}
+	if !strings.Contains(xliff, `state="new"`) {
+		t.Errorf("untranslated entry not marked new in xliff:\n%s", xliff)
+	}
+	if strings.Contains(xliff, "obsolete entry") {
+		t.Errorf("obsolete entry leaked into xliff:\n%s", xliff)
+	}
+}
+
+func TestXliffToPoRoundTrip(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	buf, err := PoToXliff(poFile, "zh_CN")
+	if err != nil {
+		t.Fatalf("fail to convert po to xliff: %s", err)
+	}
+	again, err := XliffToPo(buf)
+	if err != nil {
+		t.Fatalf("fail to convert xliff back to po: %s", err)
+	}
+	if len(again.Entries) != 4 {
+		t.Fatalf("round trip gives %d entries, expect 4", len(again.Entries))
+	}
+	if again.Entries[0].MsgID != "could not open '%s'" {
+		t.Errorf("bad msgid after round trip: %q", again.Entries[0].MsgID)
+	}
+	if again.Entries[0].MsgStr != "无法打开 '%s'" {
+		t.Errorf("bad msgstr after round trip: %q", again.Entries[0].MsgStr)
+	}
+	if !again.Entries[2].IsFuzzy() {
+		t.Error("fuzzy state lost in round trip")
+	}
+	if again.Entries[3].IsTranslated() {
+		t.Error("untranslated entry reported as translated after round trip")
+	}
+}
+
+func TestXliffToPoBadInput(t *testing.T) {
+	if _, err := XliffToPo([]byte("not an xliff document")); err == nil {
+		t.Error("no error for non-xml input")
+	}
+	if _, err := XliffToPo([]byte(`<?xml version="1.0"?><xliff version="1.2"></xliff>`)); err == nil {
+		t.Error("no error for xliff without file element")
+	}
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var demoPotFile = `msgid ""
+msgstr ""
+"Project-Id-Version: demo\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "could not open '%s'"
+msgstr ""
+
+msgid "one file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid "    --force    force the operation"
+msgstr ""
+`
+
+var demoPoFiles = map[string]string{
+	"zh_CN": `msgid ""
+msgstr ""
+"Project-Id-Version: demo\n"
+"Language: zh_CN\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=1; plural=0;\n"
+
+msgid "could not open '%s'"
+msgstr "无法打开 '%s'"
+
+msgid "one file"
+msgid_plural "%d files"
+msgstr[0] "%d 个文件"
+
+msgid "    --force    force the operation"
+msgstr ""
+`,
+	"fr": `msgid ""
+msgstr ""
+"Project-Id-Version: demo\n"
+"Language: fr\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "could not open '%s'"
+msgstr "impossible d'ouvrir '%s'"
+
+#, fuzzy
+msgid "one file"
+msgid_plural "%d files"
+msgstr[0] "un fichier"
+msgstr[1] "%d fichiers"
+
+msgid "    --force    force the operation"
+msgstr ""
+`,
+}
+
+var demoAgentScript = `#!/bin/sh
+# Echo agent of the demo repository: pretends to work on a po file,
+# but changes nothing.
+echo "echo agent: working on $*"
+`
+
+var demoConfigFile = `# Configuration of the demo repository.
+agent:
+  cmd: "./agent.sh {source}"
+`
+
+// CmdDemoInit implements demo init sub command. It creates a
+// throwaway git repository with a miniature pot file, a couple of po
+// files, and a configured echo agent, so every command can be
+// exercised end-to-end without touching a real git.git checkout.
+func CmdDemoInit(workDir string) bool {
+	if Exist(workDir) {
+		log.Errorf(`fail to init demo repository, "%s" already exists`, workDir)
+		return false
+	}
+	if err := os.MkdirAll(filepath.Join(workDir, PoDir), 0755); err != nil {
+		log.Error(err)
+		return false
+	}
+
+	files := map[string]string{
+		filepath.Join(PoDir, GitPot): demoPotFile,
+		"agent.sh":                   demoAgentScript,
+		ConfigFileName + ".yaml":     demoConfigFile,
+	}
+	for locale, content := range demoPoFiles {
+		files[filepath.Join(PoDir, locale+".po")] = content
+	}
+	for fileName, content := range files {
+		mode := os.FileMode(0644)
+		if filepath.Ext(fileName) == ".sh" {
+			mode = 0755
+		}
+		if err := ioutil.WriteFile(filepath.Join(workDir, fileName), []byte(content), mode); err != nil {
+			log.Error(err)
+			return false
+		}
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=demo", "-c", "user.email=demo@localhost",
+			"commit", "-q", "-m", "Initialize demo repository"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = workDir
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			log.Errorf("fail to run git %v: %s", args, err)
+			return false
+		}
+	}
+	fmt.Printf("Demo repository created in \"%s\". Try:\n\n", workDir)
+	fmt.Printf("    cd %s\n", workDir)
+	fmt.Printf("    git-po-helper stats\n")
+	fmt.Printf("    git-po-helper agent-run translate po/zh_CN.po\n")
+	return true
+}
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Changes reported by the diff-po command.
+const (
+	PoDiffAdded   = "added"
+	PoDiffRemoved = "removed"
+	PoDiffChanged = "changed"
+	PoDiffFuzzy   = "fuzzy"
+)
+
+// PoDiffEntry is one entry-level difference between two po files.
+type PoDiffEntry struct {
+	Change  string `json:"change"`
+	MsgCtxt string `json:"msgctxt,omitempty"`
+	MsgID   string `json:"msgid"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+}
+
+// label returns the msgid of a difference for the text output, with
+// the msgctxt appended to tell apart entries sharing a msgid.
+func (v *PoDiffEntry) label() string {
+	if v.MsgCtxt != "" {
+		return fmt.Sprintf("%s (msgctxt: %s)", v.MsgID, v.MsgCtxt)
+	}
+	return v.MsgID
+}
+
+// loadPoFileOrBlob loads a po file from the worktree, or from a
+// "<commit>:<path>" spec such as "HEAD~1:po/zh_CN.po".
+func loadPoFileOrBlob(spec string) (*PoFile, error) {
+	if strings.Contains(spec, ":") && !Exist(spec) {
+		cmd := exec.Command("git", "show", spec)
+		cmd.Dir = GitRootDir
+		buf, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf(`fail to load blob "%s": %s`, spec, err)
+		}
+		return ParsePoData(buf, spec)
+	}
+	return LoadPoFile(spec)
+}
+
+// DiffPoFiles compares two po files entry-by-entry, and reports
+// added, removed, changed, and newly-fuzzy entries. The header entry
+// is ignored.
+func DiffPoFiles(oldFile, newFile *PoFile) []PoDiffEntry {
+	var diffs []PoDiffEntry
+
+	oldEntries := make(map[string]*PoEntry)
+	for _, entry := range oldFile.Entries {
+		if entry.MsgID == "" || entry.Obsolete {
+			continue
+		}
+		oldEntries[entry.Key()] = entry
+	}
+	for _, entry := range newFile.Entries {
+		if entry.MsgID == "" || entry.Obsolete {
+			continue
+		}
+		oldEntry, ok := oldEntries[entry.Key()]
+		if !ok {
+			diffs = append(diffs, PoDiffEntry{
+				Change:  PoDiffAdded,
+				MsgCtxt: entry.MsgCtxt,
+				MsgID:   entry.MsgID,
+				New:     entry.MsgStr,
+			})
+			continue
+		}
+		delete(oldEntries, entry.Key())
+		if entry.IsFuzzy() && !oldEntry.IsFuzzy() {
+			diffs = append(diffs, PoDiffEntry{
+				Change:  PoDiffFuzzy,
+				MsgCtxt: entry.MsgCtxt,
+				MsgID:   entry.MsgID,
+				Old:     oldEntry.MsgStr,
+				New:     entry.MsgStr,
+			})
+			continue
+		}
+		if entry.MsgStr != oldEntry.MsgStr {
+			diffs = append(diffs, PoDiffEntry{
+				Change:  PoDiffChanged,
+				MsgCtxt: entry.MsgCtxt,
+				MsgID:   entry.MsgID,
+				Old:     oldEntry.MsgStr,
+				New:     entry.MsgStr,
+			})
+		}
+	}
+	for _, entry := range oldEntries {
+		diffs = append(diffs, PoDiffEntry{
+			Change:  PoDiffRemoved,
+			MsgCtxt: entry.MsgCtxt,
+			MsgID:   entry.MsgID,
+			Old:     entry.MsgStr,
+		})
+	}
+	return diffs
+}
+
+// CmdDiffPo implements diff-po sub command. It compares two po files,
+// which may be worktree files or "<commit>:<path>" specs, and reports
+// entry-level differences readable for wrapped po strings.
+func CmdDiffPo(oldSpec, newSpec string) bool {
+	oldFile, err := loadPoFileOrBlob(oldSpec)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	newFile, err := loadPoFileOrBlob(newSpec)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	diffs := DiffPoFiles(oldFile, newFile)
+
+	switch format := FlagDiffPoFormat(); format {
+	case "", "text":
+		for _, diff := range diffs {
+			switch diff.Change {
+			case PoDiffAdded:
+				fmt.Printf("+ %s\n      msgstr: %s\n", diff.label(), diff.New)
+			case PoDiffRemoved:
+				fmt.Printf("- %s\n      msgstr: %s\n", diff.label(), diff.Old)
+			case PoDiffChanged:
+				fmt.Printf("! %s\n  old msgstr: %s\n  new msgstr: %s\n",
+					diff.label(), diff.Old, diff.New)
+			case PoDiffFuzzy:
+				fmt.Printf("~ %s (newly fuzzy)\n  old msgstr: %s\n  new msgstr: %s\n",
+					diff.label(), diff.Old, diff.New)
+			}
+		}
+		log.Infof("%d entries differ between \"%s\" and \"%s\"",
+			len(diffs), oldSpec, newSpec)
+	case "json":
+		buf, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		fmt.Println(string(buf))
+	default:
+		log.Errorf(`unknown format "%s", expect "text" or "json"`, format)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package util
+
+import (
+	"testing"
+)
+
+var diffPoOld = `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "apple"
+msgstr "苹果"
+
+msgid "banana"
+msgstr "香蕉"
+
+msgid "cherry"
+msgstr "樱桃"
+
+msgid "elderberry"
+msgstr "接骨木莓"
+
+#~ msgid "obsolete entry"
+#~ msgstr "过时条目"
+`
+
+var diffPoNew = `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "apple"
+msgstr "苹果"
+
+msgid "banana"
+msgstr "大香蕉"
+
+#, fuzzy
+msgid "cherry"
+msgstr "樱桃"
+
+msgid "date"
+msgstr "枣"
+`
+
+func TestDiffPoFiles(t *testing.T) {
+	oldFile, err := ParsePoData([]byte(diffPoOld), "old.po")
+	if err != nil {
+		t.Fatalf("fail to parse old po data: %s", err)
+	}
+	newFile, err := ParsePoData([]byte(diffPoNew), "new.po")
+	if err != nil {
+		t.Fatalf("fail to parse new po data: %s", err)
+	}
+	diffs := DiffPoFiles(oldFile, newFile)
+	if len(diffs) != 4 {
+		t.Fatalf("got %d diffs, expect 4: %+v", len(diffs), diffs)
+	}
+	byMsgID := make(map[string]PoDiffEntry)
+	for _, diff := range diffs {
+		byMsgID[diff.MsgID] = diff
+	}
+	if diff := byMsgID["banana"]; diff.Change != PoDiffChanged ||
+		diff.Old != "香蕉" || diff.New != "大香蕉" {
+		t.Errorf("bad diff of changed entry: %+v", diff)
+	}
+	if diff := byMsgID["cherry"]; diff.Change != PoDiffFuzzy {
+		t.Errorf("bad diff of newly fuzzy entry: %+v", diff)
+	}
+	if diff := byMsgID["date"]; diff.Change != PoDiffAdded || diff.New != "枣" {
+		t.Errorf("bad diff of added entry: %+v", diff)
+	}
+	if diff := byMsgID["elderberry"]; diff.Change != PoDiffRemoved ||
+		diff.Old != "接骨木莓" {
+		t.Errorf("bad diff of removed entry: %+v", diff)
+	}
+	if _, ok := byMsgID["apple"]; ok {
+		t.Error("unchanged entry reported in diff")
+	}
+	if _, ok := byMsgID["obsolete entry"]; ok {
+		t.Error("obsolete entry reported in diff")
+	}
+}
+
+func TestDiffPoFilesMsgCtxt(t *testing.T) {
+	oldData := `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgctxt "column"
+msgid "Status"
+msgstr "状态"
+
+msgid "Status"
+msgstr "状态"
+`
+	newData := `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+msgctxt "column"
+msgid "Status"
+msgstr "状态栏"
+
+msgid "Status"
+msgstr "状态"
+`
+	oldFile, err := ParsePoData([]byte(oldData), "old.po")
+	if err != nil {
+		t.Fatalf("fail to parse old po data: %s", err)
+	}
+	newFile, err := ParsePoData([]byte(newData), "new.po")
+	if err != nil {
+		t.Fatalf("fail to parse new po data: %s", err)
+	}
+	diffs := DiffPoFiles(oldFile, newFile)
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, expect 1: %+v", len(diffs), diffs)
+	}
+	diff := diffs[0]
+	if diff.Change != PoDiffChanged || diff.MsgCtxt != "column" ||
+		diff.MsgID != "Status" || diff.New != "状态栏" {
+		t.Errorf("bad diff of entry with msgctxt: %+v", diff)
+	}
+	if diff.label() != "Status (msgctxt: column)" {
+		t.Errorf("bad label of entry with msgctxt: %q", diff.label())
+	}
+}
+
+func TestDiffPoFilesNoChanges(t *testing.T) {
+	oldFile, err := ParsePoData([]byte(diffPoOld), "old.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	newFile, err := ParsePoData([]byte(diffPoOld), "old.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	if diffs := DiffPoFiles(oldFile, newFile); len(diffs) != 0 {
+		t.Errorf("got %d diffs comparing a file with itself: %+v", len(diffs), diffs)
+	}
+}
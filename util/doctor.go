@@ -0,0 +1,193 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// doctorCheck is the outcome of one environment check of the doctor
+// sub command, with a remediation hint shown when the check fails.
+type doctorCheck struct {
+	Name   string
+	OK     bool
+	Detail string
+	Hint   string
+}
+
+// doctorGitRepo checks the command runs inside a git worktree with a
+// "po/" directory.
+func doctorGitRepo() []doctorCheck {
+	var checks []doctorCheck
+	repo, err := NewRepository(".")
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name:   "git repository",
+			Detail: strings.TrimSpace(err.Error()),
+			Hint:   "run git-po-helper from the root of a git worktree with a po/ directory, such as a clone of git.git",
+		})
+		return checks
+	}
+	checks = append(checks, doctorCheck{
+		Name:   "git repository",
+		OK:     true,
+		Detail: repo.WorkDir(),
+	})
+	check := doctorCheck{
+		Name: "po directory",
+		Hint: `create the "po/" directory, or run "git-po-helper init XX.po" in a clone of git.git`,
+	}
+	if fi, err := os.Stat(filepath.Join(repo.WorkDir(), PoDir)); err == nil && fi.IsDir() {
+		check.OK = true
+		check.Detail = filepath.Join(repo.WorkDir(), PoDir)
+	} else {
+		check.Detail = fmt.Sprintf(`no "%s/" directory in "%s"`, PoDir, repo.WorkDir())
+	}
+	checks = append(checks, check)
+	return checks
+}
+
+// doctorTools checks git and the gettext programs are found on PATH,
+// and shows their versions.
+func doctorTools() []doctorCheck {
+	var checks []doctorCheck
+	for _, tool := range gettextTools {
+		check := doctorCheck{Name: tool}
+		if _, err := exec.LookPath(tool); err != nil {
+			check.Detail = "not installed"
+			if tool == "git" {
+				check.Hint = "install git"
+			} else {
+				check.Hint = "install the gettext package which provides " + tool
+			}
+		} else {
+			check.OK = true
+			check.Detail = toolVersion(tool)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorConfig checks the syntax of the configuration files.
+func doctorConfig() []doctorCheck {
+	var checks []doctorCheck
+	for _, fileName := range configFiles() {
+		if !Exist(fileName) {
+			continue
+		}
+		check := doctorCheck{Name: "config " + fileName}
+		v := viper.New()
+		v.SetConfigFile(fileName)
+		v.SetConfigType("yaml")
+		if err := v.ReadInConfig(); err != nil {
+			check.Detail = strings.TrimSpace(err.Error())
+			check.Hint = "fix the YAML syntax of the config file"
+		} else {
+			check.OK = true
+			check.Detail = fmt.Sprintf("%d settings", len(v.AllKeys()))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorAgents checks the agent CLIs named in the config files are
+// found on PATH.
+func doctorAgents() []doctorCheck {
+	var checks []doctorCheck
+	agents := configuredAgentCommands()
+	for name, cmd := range agents {
+		check := doctorCheck{Name: "agent " + name}
+		if _, err := exec.LookPath(cmd); err != nil {
+			check.Detail = fmt.Sprintf(`"%s" is not found on PATH`, cmd)
+			check.Hint = fmt.Sprintf(`install "%s", or fix "agents.%s.cmd" in the config file`, cmd, name)
+		} else {
+			check.OK = true
+			check.Detail = cmd
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// doctorWritable checks the po directory is writable, by creating and
+// removing a probe file.
+func doctorWritable() []doctorCheck {
+	if fi, err := os.Stat(PoDir); err != nil || !fi.IsDir() {
+		return nil
+	}
+	check := doctorCheck{
+		Name: "write permission",
+		Hint: `fix the permissions of the "po/" directory`,
+	}
+	probe, err := ioutil.TempFile(PoDir, ".doctor-")
+	if err != nil {
+		check.Detail = strings.TrimSpace(err.Error())
+	} else {
+		probe.Close()
+		os.Remove(probe.Name())
+		check.OK = true
+		check.Detail = fmt.Sprintf(`"%s/" is writable`, PoDir)
+	}
+	return []doctorCheck{check}
+}
+
+// doctorLocale checks the locale environment is UTF-8, which the
+// gettext tools need to handle po files without mangling.
+func doctorLocale() []doctorCheck {
+	check := doctorCheck{
+		Name: "locale",
+		Hint: "export LANG or LC_ALL with a UTF-8 locale, such as C.UTF-8",
+	}
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	switch {
+	case locale == "":
+		check.Detail = "LANG and LC_ALL are unset"
+	case strings.Contains(strings.ToUpper(locale), "UTF-8"),
+		strings.Contains(strings.ToUpper(locale), "UTF8"):
+		check.OK = true
+		check.Detail = locale
+	default:
+		check.Detail = fmt.Sprintf(`"%s" is not a UTF-8 locale`, locale)
+	}
+	return []doctorCheck{check}
+}
+
+// CmdDoctor implements doctor sub command, diagnosing the environment
+// and printing a remediation hint for each failed check.
+func CmdDoctor() bool {
+	var (
+		ret    = true
+		checks []doctorCheck
+	)
+	checks = append(checks, doctorGitRepo()...)
+	checks = append(checks, doctorTools()...)
+	checks = append(checks, doctorConfig()...)
+	checks = append(checks, doctorAgents()...)
+	checks = append(checks, doctorWritable()...)
+	checks = append(checks, doctorLocale()...)
+	for _, check := range checks {
+		status := "ok"
+		if !check.OK {
+			status = "FAIL"
+			ret = false
+		}
+		fmt.Printf("%4s: %s: %s\n", status, check.Name, check.Detail)
+		if !check.OK && check.Hint != "" {
+			fmt.Printf("      hint: %s\n", check.Hint)
+		}
+	}
+	return ret
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"strings"
+
 	"github.com/spf13/viper"
 )
 
@@ -37,9 +39,16 @@ func FlagNoGPG() bool {
 	return FlagGitHubAction() || viper.GetBool("check--no-gpg") || viper.GetBool("check-commits--no-gpg")
 }
 
+// FlagStrict returns option "--strict", which promotes warnings to
+// hard errors for use in CI gating.
+func FlagStrict() bool {
+	return viper.GetBool("strict")
+}
+
 // FlagReportTyposAsErrors returns option "--report-typos-as-errors".
 func FlagReportTyposAsErrors() bool {
-	return viper.GetBool("check-po--report-typos-as-errors") ||
+	return FlagStrict() ||
+		viper.GetBool("check-po--report-typos-as-errors") ||
 		viper.GetBool("check-commits--report-typos-as-errors") ||
 		viper.GetBool("check--report-typos-as-errors")
 }
@@ -51,11 +60,494 @@ func FlagIgnoreTypos() bool {
 		viper.GetBool("check--ignore-typos")
 }
 
+// FlagReportAlignmentAsErrors returns option "--report-alignment-as-errors".
+func FlagReportAlignmentAsErrors() bool {
+	return FlagStrict() ||
+		viper.GetBool("check-po--report-alignment-as-errors") ||
+		viper.GetBool("check-commits--report-alignment-as-errors") ||
+		viper.GetBool("check--report-alignment-as-errors")
+}
+
+// FlagReportPlaceholdersAsErrors returns option
+// "--report-placeholders-as-errors".
+func FlagReportPlaceholdersAsErrors() bool {
+	return FlagStrict() ||
+		viper.GetBool("check-po--report-placeholders-as-errors") ||
+		viper.GetBool("check-commits--report-placeholders-as-errors") ||
+		viper.GetBool("check--report-placeholders-as-errors")
+}
+
+// FlagReportPunctuationAsErrors returns option
+// "--report-punctuation-as-errors".
+func FlagReportPunctuationAsErrors() bool {
+	return FlagStrict() ||
+		viper.GetBool("check-po--report-punctuation-as-errors") ||
+		viper.GetBool("check-commits--report-punctuation-as-errors") ||
+		viper.GetBool("check--report-punctuation-as-errors")
+}
+
+// FlagIgnoreAlignment returns option "--ignore-alignment".
+func FlagIgnoreAlignment() bool {
+	return viper.GetBool("check-po--ignore-alignment") ||
+		viper.GetBool("check-commits--ignore-alignment") ||
+		viper.GetBool("check--ignore-alignment")
+}
+
+// FlagGlossary returns option "--glossary".
+func FlagGlossary() bool {
+	return viper.GetBool("check-po--glossary") ||
+		viper.GetBool("check-commits--glossary") ||
+		viper.GetBool("check--glossary")
+}
+
+// FlagNoFuzzy returns option "--no-fuzzy", which reports fuzzy entries
+// as errors.
+func FlagNoFuzzy() bool {
+	return viper.GetBool("check-po--no-fuzzy") ||
+		viper.GetBool("check-commits--no-fuzzy") ||
+		viper.GetBool("check--no-fuzzy")
+}
+
+// FlagNoObsolete returns option "--no-obsolete", which reports obsolete
+// ("#~") entries as errors.
+func FlagNoObsolete() bool {
+	return viper.GetBool("check-po--no-obsolete") ||
+		viper.GetBool("check-commits--no-obsolete") ||
+		viper.GetBool("check--no-obsolete")
+}
+
+// FlagRequireHeaderFields returns option "--require-header-fields", a
+// comma-separated list of header fields which must be present and
+// non-empty.
+func FlagRequireHeaderFields() []string {
+	var fields []string
+	for _, key := range []string{
+		"check-po--require-header-fields",
+		"check-commits--require-header-fields",
+		"check--require-header-fields",
+	} {
+		for _, field := range strings.Split(viper.GetString(key), ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// FlagFixEncoding returns option "--fix" of check-encoding command.
+func FlagFixEncoding() bool {
+	return viper.GetBool("check-encoding--fix")
+}
+
+// FlagEncodingRange returns option "--range" of check-encoding command.
+func FlagEncodingRange() string {
+	return viper.GetString("check-encoding--range")
+}
+
+// FlagFixHeader returns option "--fix" of check-header command.
+func FlagFixHeader() bool {
+	return viper.GetBool("check-header--fix")
+}
+
+// FlagConfig returns option "--config", an explicit config file which
+// replaces the system, user, and project config files.
+func FlagConfig() string {
+	return viper.GetString("config")
+}
+
+// FlagShowConfigOrigin returns option "--origin" of show-config
+// command.
+func FlagShowConfigOrigin() bool {
+	return viper.GetBool("show-config--origin")
+}
+
+// FlagSpell returns option "--spell" of check-po command.
+func FlagSpell() bool {
+	return viper.GetBool("check-po--spell")
+}
+
+// FlagPoOnly returns option "--po-only" of check-commits command.
+func FlagPoOnly() bool {
+	return viper.GetBool("check-commits--po-only") ||
+		viper.GetBool("check--po-only")
+}
+
+// FlagStatsConsistency returns option "--stats-consistency" of
+// check-commits command.
+func FlagStatsConsistency() bool {
+	return viper.GetBool("check-commits--stats-consistency")
+}
+
 // FlagCore returns option "--core".
 func FlagCore() bool {
 	return viper.GetBool("check--core") || viper.GetBool("check-po--core")
 }
 
+// FlagAgentCmd returns option "--agent-cmd", which falls back to config
+// variable "agent.cmd".
+func FlagAgentCmd() string {
+	if cmd := viper.GetString("agent-run--agent-cmd"); cmd != "" {
+		return cmd
+	}
+	if cmd := viper.GetString("agent-test--agent-cmd"); cmd != "" {
+		return cmd
+	}
+	return viper.GetString("agent.cmd")
+}
+
+// FlagAgentTestAgents returns option "--agents" of agent-test command,
+// a comma-separated list of named agents from the config file.
+func FlagAgentTestAgents() []string {
+	var agents []string
+	for _, name := range strings.Split(viper.GetString("agent-test--agents"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			agents = append(agents, name)
+		}
+	}
+	return agents
+}
+
+// FlagAgentTestHTMLReport returns option "--html-report" of agent-test command.
+func FlagAgentTestHTMLReport() string {
+	return viper.GetString("agent-test--html-report")
+}
+
+// FlagAgentHistoryAgent returns option "--agent" of agent-test history command.
+func FlagAgentHistoryAgent() string {
+	return viper.GetString("agent-test-history--agent")
+}
+
+// FlagAgentHistoryAction returns option "--action" of agent-test history command.
+func FlagAgentHistoryAction() string {
+	return viper.GetString("agent-test-history--action")
+}
+
+// FlagAgentLogDir returns option "--log-dir", which falls back to
+// config variable "agent_run.log_dir".
+func FlagAgentLogDir() string {
+	if dir := viper.GetString("agent-run--log-dir"); dir != "" {
+		return dir
+	}
+	return viper.GetString("agent_run.log_dir")
+}
+
+// FlagAgentTag returns option "--tag" of agent-run command.
+func FlagAgentTag() string {
+	return viper.GetString("agent-run--tag")
+}
+
+// FlagAgentNote returns option "--note" of agent-run command.
+func FlagAgentNote() string {
+	return viper.GetString("agent-run--note")
+}
+
+// FlagRunID returns option "--run-id" of agent-run command, to resume
+// or associate artifacts of an earlier run.
+func FlagRunID() string {
+	return viper.GetString("agent-run--run-id")
+}
+
+// FlagAgentRecord returns option "--record" of agent-run command, the
+// directory recording the agent session.
+func FlagAgentRecord() string {
+	return viper.GetString("agent-run--record")
+}
+
+// FlagAgentReplay returns option "--replay" of agent-run command, the
+// directory holding a recorded agent session to replay.
+func FlagAgentReplay() string {
+	return viper.GetString("agent-run--replay")
+}
+
+// FlagParseLogOutput returns option "--agent-output" of parse-log
+// command.
+func FlagParseLogOutput() string {
+	return viper.GetString("parse-log--agent-output")
+}
+
+// FlagParseLogTolerant returns option "--tolerant" of parse-log
+// command.
+func FlagParseLogTolerant() bool {
+	return viper.GetBool("parse-log--tolerant")
+}
+
+// FlagParseLogFormat returns option "--format" of parse-log command.
+func FlagParseLogFormat() string {
+	return viper.GetString("parse-log--format")
+}
+
+// FlagParseLogExport returns option "--export" of parse-log command.
+func FlagParseLogExport() string {
+	return viper.GetString("parse-log--export")
+}
+
+// FlagParseLogStats returns option "--stats" of parse-log command.
+func FlagParseLogStats() string {
+	return viper.GetString("parse-log--stats")
+}
+
+// FlagAgentDryRun returns option "--dry-run" of agent-run command, and
+// also honors the global "--dryrun" option.
+func FlagAgentDryRun() bool {
+	return viper.GetBool("agent-run--dry-run") || viper.GetBool("dryrun")
+}
+
+// FlagAgentSandbox returns option "--sandbox" of agent-run command.
+func FlagAgentSandbox() bool {
+	return viper.GetBool("agent-run--sandbox")
+}
+
+// FlagAgentOutput returns option "--agent-output" of agent-run
+// command, one of "quiet", "summary" or "full".
+func FlagAgentOutput() string {
+	return viper.GetString("agent-run--agent-output")
+}
+
+// FlagAgentBatch returns option "--batch".
+func FlagAgentBatch() bool {
+	return viper.GetBool("agent-run-translate--batch")
+}
+
+// FlagAgentAllLanguages returns option "--all-languages".
+func FlagAgentAllLanguages() bool {
+	return viper.GetBool("agent-run-translate--all-languages")
+}
+
+// FlagTUI returns option "--tui" of agent-run and agent-test commands.
+func FlagTUI() bool {
+	return viper.GetBool("agent-run--tui") ||
+		viper.GetBool("agent-test--tui")
+}
+
+// FlagAgentJobs returns option "--jobs".
+func FlagAgentJobs() int {
+	return viper.GetInt("agent-run-translate--jobs")
+}
+
+// FlagAgentResume returns option "--resume".
+func FlagAgentResume() bool {
+	return viper.GetBool("agent-run-translate--resume")
+}
+
+// FlagAutoCommit returns option "--commit" of the commands which can
+// commit their own results.
+func FlagAutoCommit() bool {
+	return viper.GetBool("agent-run-translate--commit") ||
+		viper.GetBool("update--commit") ||
+		viper.GetBool("update-pot--commit")
+}
+
+// FlagCommitSignoff returns option "--signoff".
+func FlagCommitSignoff() bool {
+	return viper.GetBool("agent-run-translate--signoff") ||
+		viper.GetBool("update--signoff") ||
+		viper.GetBool("update-pot--signoff")
+}
+
+// FlagCommitGPGSign returns option "--gpg-sign".
+func FlagCommitGPGSign() bool {
+	return viper.GetBool("agent-run-translate--gpg-sign") ||
+		viper.GetBool("update--gpg-sign") ||
+		viper.GetBool("update-pot--gpg-sign")
+}
+
+// FlagCommitMessageTemplate returns option "--message-template".
+func FlagCommitMessageTemplate() string {
+	for _, key := range []string{
+		"agent-run-translate--message-template",
+		"update--message-template",
+		"update-pot--message-template",
+	} {
+		if template := viper.GetString(key); template != "" {
+			return template
+		}
+	}
+	return ""
+}
+
+// FlagFreshnessThreshold returns option "--threshold" of check-freshness command.
+func FlagFreshnessThreshold() int {
+	return viper.GetInt("check-freshness--threshold")
+}
+
+// FlagFreshnessRemote returns option "--remote" of check-freshness command.
+func FlagFreshnessRemote() string {
+	return viper.GetString("check-freshness--remote")
+}
+
+// FlagReviewApply returns option "--apply" of agent-run review command.
+func FlagReviewApply() bool {
+	return viper.GetBool("agent-run-review--apply")
+}
+
+// FlagReviewAnnotate returns option "--annotate" of agent-run review
+// command.
+func FlagReviewAnnotate() bool {
+	return viper.GetBool("agent-run-review--annotate")
+}
+
+// FlagReviewResume returns option "--resume" of agent-run review command.
+func FlagReviewResume() bool {
+	return viper.GetBool("agent-run-review--resume")
+}
+
+// FlagReviewOnlyChanged returns option "--only-changed" of agent-run
+// review command, the ref translations are compared against.
+func FlagReviewOnlyChanged() string {
+	return viper.GetString("agent-run-review--only-changed")
+}
+
+// FlagAgentTM returns option "--tm" of agent-run translate command.
+func FlagAgentTM() bool {
+	return viper.GetBool("agent-run-translate--tm")
+}
+
+// FlagReviewNoCache returns option "--no-cache" of agent-run review
+// command.
+func FlagReviewNoCache() bool {
+	return viper.GetBool("agent-run-review--no-cache")
+}
+
+// FlagReviewReprompt returns option "--reprompt" of agent-run review
+// command.
+func FlagReviewReprompt() bool {
+	return viper.GetBool("agent-run-review--reprompt")
+}
+
+// FlagReviewExampleLang returns option "--lang" of add-review-example command.
+func FlagReviewExampleLang() string {
+	return viper.GetString("add-review-example--lang")
+}
+
+// FlagReviewExampleMsgID returns option "--msgid" of add-review-example command.
+func FlagReviewExampleMsgID() string {
+	return viper.GetString("add-review-example--msgid")
+}
+
+// FlagReviewExampleMsgStr returns option "--msgstr" of add-review-example command.
+func FlagReviewExampleMsgStr() string {
+	return viper.GetString("add-review-example--msgstr")
+}
+
+// FlagReviewExampleIssue returns option "--issue" of add-review-example command.
+func FlagReviewExampleIssue() string {
+	return viper.GetString("add-review-example--issue")
+}
+
+// FlagTeamLanguage returns option "--language" of team add command.
+func FlagTeamLanguage() string {
+	return viper.GetString("team-add--language")
+}
+
+// FlagTeamRepository returns option "--repository" of team add command.
+func FlagTeamRepository() string {
+	return viper.GetString("team-add--repository")
+}
+
+// FlagTeamLeader returns option "--leader" of team add command.
+func FlagTeamLeader() string {
+	return viper.GetString("team-add--leader")
+}
+
+// FlagTeamMembers returns option "--member" of team add command, which
+// can be given multiple times.
+func FlagTeamMembers() []string {
+	return viper.GetStringSlice("team-add--member")
+}
+
+// FlagHandoffOutput returns option "--output" of handoff build command.
+func FlagHandoffOutput() string {
+	return viper.GetString("handoff-build--output")
+}
+
+// FlagAnnotateMsgID returns option "--msgid" of annotate command.
+func FlagAnnotateMsgID() string {
+	return viper.GetString("annotate--msgid")
+}
+
+// FlagAnnotateNote returns option "--note" of annotate command.
+func FlagAnnotateNote() string {
+	return viper.GetString("annotate--note")
+}
+
+// FlagConvertTo returns option "--to" of convert command.
+func FlagConvertTo() string {
+	return viper.GetString("convert--to")
+}
+
+// FlagConvertFrom returns option "--from" of convert command.
+func FlagConvertFrom() string {
+	return viper.GetString("convert--from")
+}
+
+// FlagConvertOutput returns option "--output" of convert command.
+func FlagConvertOutput() string {
+	return viper.GetString("convert--output")
+}
+
+// FlagPoJSONOutput returns option "--output" of po-json command.
+func FlagPoJSONOutput() string {
+	return viper.GetString("po-json--output")
+}
+
+// FlagPoJSONRange returns option "--range" of po-json export command.
+func FlagPoJSONRange() string {
+	return viper.GetString("po-json-export--range")
+}
+
+// FlagMsgSelectUntranslated returns option "--untranslated" of msg-select command.
+func FlagMsgSelectUntranslated() bool {
+	return viper.GetBool("msg-select--untranslated")
+}
+
+// FlagMsgSelectFuzzy returns option "--fuzzy" of msg-select command.
+func FlagMsgSelectFuzzy() bool {
+	return viper.GetBool("msg-select--fuzzy")
+}
+
+// FlagMsgSelectGrep returns option "--grep" of msg-select command.
+func FlagMsgSelectGrep() string {
+	return viper.GetString("msg-select--grep")
+}
+
+// FlagMsgSelectOutput returns option "--output" of msg-select command.
+func FlagMsgSelectOutput() string {
+	return viper.GetString("msg-select--output")
+}
+
+// FlagDiffPoFormat returns option "--format" of diff-po command.
+func FlagDiffPoFormat() string {
+	return viper.GetString("diff-po--format")
+}
+
+// FlagStatsFormat returns option "--format" of stats command.
+func FlagStatsFormat() string {
+	return viper.GetString("stats--format")
+}
+
+// FlagProgressFd returns option "--progress-fd".
+func FlagProgressFd() int {
+	return viper.GetInt("progress-fd")
+}
+
+// FlagServeListen returns option "--listen" of serve command.
+func FlagServeListen() string {
+	return viper.GetString("serve--listen")
+}
+
+// FlagNoRepo returns option "--no-repo", which makes commands operate
+// on arbitrary po files outside of a git repository.
+func FlagNoRepo() bool {
+	return viper.GetBool("no-repo")
+}
+
+// FlagOutputStyle returns option "--output-style", one of "text" or
+// "github".
+func FlagOutputStyle() string {
+	return viper.GetString("output-style")
+}
+
 // FlagNoGettext14 returns option "--no-gettext-back-compatible".
 func FlagNoGettext14() bool {
 	return FlagGitHubAction() || viper.GetBool("no-gettext-back-compatible")
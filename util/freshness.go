@@ -0,0 +1,91 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// localeFreshness records how far a language fell behind the pot file.
+type localeFreshness struct {
+	Locale string
+	Behind int
+}
+
+// CmdCheckFreshness implements check-freshness sub command, suited for
+// a scheduled job. It fetches the upstream master branch, regenerates
+// "po/git.pot", and reports languages which fell behind by more than
+// the given threshold of entries.
+func CmdCheckFreshness() bool {
+	var stale []localeFreshness
+
+	remote := FlagFreshnessRemote()
+	if remote != "" {
+		cmd := exec.Command("git", "fetch", remote, "master")
+		cmd.Dir = GitRootDir
+		cmd.Stderr = os.Stderr
+		log.Infof("fetching master branch from remote \"%s\"", remote)
+		if err := cmd.Run(); err != nil {
+			log.Errorf("fail to fetch remote \"%s\": %s", remote, err)
+			return false
+		}
+	}
+
+	potFile := filepath.Join(PoDir, GitPot)
+	if !Exist(potFile) {
+		log.Errorf(`fail to check freshness, "%s" does not exist`, potFile)
+		return false
+	}
+	pot, err := LoadPoFile(potFile)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	potIDs := make(map[string]bool)
+	for _, entry := range pot.Entries {
+		if !entry.Obsolete {
+			potIDs[entry.Key()] = true
+		}
+	}
+
+	poFiles := listPoFiles()
+
+	threshold := FlagFreshnessThreshold()
+	for _, fileName := range poFiles {
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		poFile, err := LoadPoFile(fileName)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		behind := 0
+		translated := make(map[string]bool)
+		for _, entry := range poFile.Entries {
+			if !entry.Obsolete && entry.IsTranslated() && !entry.IsFuzzy() {
+				translated[entry.Key()] = true
+			}
+		}
+		for msgID := range potIDs {
+			if !translated[msgID] {
+				behind++
+			}
+		}
+		if behind > threshold {
+			stale = append(stale, localeFreshness{Locale: locale, Behind: behind})
+		}
+	}
+
+	if len(stale) == 0 {
+		log.Infof("all languages are up to date (threshold: %d entries)", threshold)
+		return true
+	}
+	fmt.Printf("Languages behind %s by more than %d entries:\n\n", potFile, threshold)
+	for _, item := range stale {
+		fmt.Printf("* %s: %d entries behind\n", item.Locale, item.Behind)
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GateResult is the recorded outcome of one gate, such as a syntax
+// check, a score threshold, or a budget limit.
+type GateResult struct {
+	Name    string `json:"name"`
+	Ok      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+var (
+	gateMutex          sync.Mutex
+	gateResults        []GateResult
+	gateSummaryEnabled bool
+)
+
+// EnableGateSummary turns on the gate summary matrix for the current
+// command. Only orchestration commands such as agent-run and
+// agent-test opt in; the plain check commands keep their exact output
+// for scripts and tests which parse it.
+func EnableGateSummary() {
+	gateMutex.Lock()
+	defer gateMutex.Unlock()
+	gateSummaryEnabled = true
+}
+
+// ReportGate records the outcome of one gate. All recorded gates are
+// aggregated into one pass/fail matrix when the command exits, so it
+// is obvious which gate failed instead of hunting through scattered
+// error messages.
+func ReportGate(name string, ok bool, format string, args ...interface{}) {
+	gateMutex.Lock()
+	defer gateMutex.Unlock()
+	gateResults = append(gateResults, GateResult{
+		Name:    name,
+		Ok:      ok,
+		Message: fmt.Sprintf(format, args...),
+	})
+	tuiReportGate(name, ok)
+}
+
+// ShowGateSummary prints the pass/fail matrix of all recorded gates,
+// serializes it to ".git-po-helper/gates.json", and returns true only
+// if every gate passed. Nothing is shown and nothing fails unless the
+// command opted in with EnableGateSummary, or if no gate was recorded,
+// or if only one gate was recorded and it passed.
+func ShowGateSummary() bool {
+	gateMutex.Lock()
+	defer gateMutex.Unlock()
+
+	if !gateSummaryEnabled {
+		return true
+	}
+	ok := true
+	for _, gate := range gateResults {
+		if !gate.Ok {
+			ok = false
+		}
+	}
+	if len(gateResults) == 0 || (len(gateResults) == 1 && ok) {
+		return ok
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Gate\tResult\tDetail")
+	for _, gate := range gateResults {
+		status := "pass"
+		if !gate.Ok {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", gate.Name, status, gate.Message)
+	}
+	w.Flush()
+
+	if Exist(HelperDir) || os.MkdirAll(HelperDir, 0755) == nil {
+		buf, err := json.MarshalIndent(gateResults, "", "  ")
+		if err == nil {
+			err = ioutil.WriteFile(
+				filepath.Join(HelperDir, "gates.json"), append(buf, '\n'), 0644)
+		}
+		if err != nil {
+			log.Warnf("fail to save gate summary: %s", err)
+		}
+	}
+	return ok
+}
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// HelperDir is the working directory of git-po-helper inside the
+// repository, which holds run artifacts and caches.
+const HelperDir = ".git-po-helper"
+
+// gcDirs are the sub directories of HelperDir pruned by gc.
+var gcDirs = []string{"runs", "logs", "cache", "history"}
+
+// gcItem is one prunable artifact, a direct entry of a gc directory.
+type gcItem struct {
+	Path    string
+	ModTime time.Time
+	Size    int64
+}
+
+// GcMaxAge returns config variable "gc.max_age", the age in days
+// after which artifacts are pruned.
+func GcMaxAge() int {
+	if !viper.IsSet("gc.max_age") {
+		return 30
+	}
+	return viper.GetInt("gc.max_age")
+}
+
+// GcMaxSize returns config variable "gc.max_size_mb", the total size
+// in megabytes artifacts may occupy, where 0 means unlimited.
+func GcMaxSize() int64 {
+	return viper.GetInt64("gc.max_size_mb") * 1024 * 1024
+}
+
+func dirSize(path string) int64 {
+	var size int64
+
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if info != nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// CmdGc implements gc sub command. It prunes old artifacts under
+// HelperDir by the age and size policy from config, and reports
+// reclaimed space.
+func CmdGc() bool {
+	var (
+		items     []gcItem
+		reclaimed int64
+	)
+
+	for _, name := range gcDirs {
+		dir := filepath.Join(HelperDir, name)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			size := info.Size()
+			if entry.IsDir() {
+				size = dirSize(path)
+			}
+			items = append(items, gcItem{
+				Path:    path,
+				ModTime: info.ModTime(),
+				Size:    size,
+			})
+		}
+	}
+	if len(items) == 0 {
+		log.Infof(`nothing to prune in "%s"`, HelperDir)
+		return true
+	}
+
+	prune := func(item gcItem, reason string) bool {
+		if err := os.RemoveAll(item.Path); err != nil {
+			log.Errorf(`fail to prune "%s": %s`, item.Path, err)
+			return false
+		}
+		log.Infof(`pruned "%s" (%s)`, item.Path, reason)
+		reclaimed += item.Size
+		return true
+	}
+
+	// Prune artifacts older than the age policy.
+	maxAge := GcMaxAge()
+	deadline := time.Now().AddDate(0, 0, -maxAge)
+	var kept []gcItem
+	ret := true
+	for _, item := range items {
+		if maxAge > 0 && item.ModTime.Before(deadline) {
+			if !prune(item, fmt.Sprintf("older than %d days", maxAge)) {
+				ret = false
+			}
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	// Prune the oldest artifacts until the size policy is satisfied.
+	if maxSize := GcMaxSize(); maxSize > 0 {
+		var total int64
+		for _, item := range kept {
+			total += item.Size
+		}
+		sort.Slice(kept, func(i, j int) bool {
+			return kept[i].ModTime.Before(kept[j].ModTime)
+		})
+		for _, item := range kept {
+			if total <= maxSize {
+				break
+			}
+			if prune(item, "size policy") {
+				total -= item.Size
+			} else {
+				ret = false
+			}
+		}
+	}
+
+	fmt.Printf("reclaimed %.1f MiB\n", float64(reclaimed)/1024/1024)
+	return ret
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// fileLinePattern matches a "file:line" location inside a diagnostic,
+// as written by msgfmt and the po parser, so annotations can point at
+// the offending line.
+var fileLinePattern = regexp.MustCompile(`(?:^|\s)([^\s:]+\.(?:po|pot)):([0-9]+)`)
+
+// GitHubAnnotationHook renders errors and warnings as GitHub Actions
+// workflow commands, such as "::error file=po/zh_CN.po,line=123::...",
+// so findings appear inline in pull request diffs.
+type GitHubAnnotationHook struct{}
+
+// Levels lets logrus fire the hook for errors and warnings only.
+func (v *GitHubAnnotationHook) Levels() []log.Level {
+	return []log.Level{log.ErrorLevel, log.WarnLevel}
+}
+
+// Fire writes one workflow command per log entry to stdout, where the
+// GitHub Actions runner picks it up.
+func (v *GitHubAnnotationHook) Fire(entry *log.Entry) error {
+	kind := "error"
+	if entry.Level == log.WarnLevel {
+		kind = "warning"
+	}
+	location := ""
+	if m := fileLinePattern.FindStringSubmatch(entry.Message); m != nil {
+		location = fmt.Sprintf(" file=%s,line=%s", m[1], m[2])
+	}
+	// Escape data the way workflow commands require.
+	message := strings.ReplaceAll(entry.Message, "%", "%25")
+	message = strings.ReplaceAll(message, "\r", "%0D")
+	message = strings.ReplaceAll(message, "\n", "%0A")
+	fmt.Fprintf(os.Stdout, "::%s%s::%s\n", kind, location, message)
+	return nil
+}
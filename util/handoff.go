@@ -0,0 +1,174 @@
+package util
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CmdHandoffBuild implements handoff build sub command. It bundles the
+// po file of a language together with its glossary, stats, and an HTML
+// report into a zip archive for the language team lead.
+func CmdHandoffBuild(locale string) bool {
+	if _, err := GetPrettyLocaleName(locale); err != nil {
+		log.Error(err)
+		return false
+	}
+	poFile := filepath.Join(PoDir, locale+".po")
+	if !Exist(poFile) {
+		log.Errorf(`fail to build handoff package, "%s" does not exist`, poFile)
+		return false
+	}
+
+	zipName := FlagHandoffOutput()
+	if zipName == "" {
+		zipName = fmt.Sprintf("handoff-%s.zip", locale)
+	}
+	f, err := os.Create(zipName)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	defer w.Close()
+
+	addFile := func(name string, data []byte) bool {
+		entry, err := w.Create(name)
+		if err == nil {
+			_, err = entry.Write(data)
+		}
+		if err != nil {
+			log.Errorf(`fail to add "%s" to handoff package: %s`, name, err)
+			return false
+		}
+		return true
+	}
+
+	buf, err := ioutil.ReadFile(poFile)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if !addFile(filepath.Join(PoDir, locale+".po"), buf) {
+		return false
+	}
+
+	glossaryFile := filepath.Join(GlossaryDir, locale+".txt")
+	if buf, err := ioutil.ReadFile(glossaryFile); err == nil {
+		if !addFile(glossaryFile, buf) {
+			return false
+		}
+	}
+
+	stats, err := StatPoFile(poFile)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if buf, err := json.MarshalIndent(stats, "", "  "); err == nil {
+		if !addFile("stats.json", append(buf, '\n')) {
+			return false
+		}
+	}
+
+	report := []AgentTestResult{
+		{
+			Agent:      "handoff",
+			Locale:     locale,
+			Score:      int(stats.Percent()),
+			Translated: stats.Translated,
+			Total:      stats.Total,
+		},
+	}
+	tmpFile, err := ioutil.TempFile("", "report")
+	if err == nil {
+		tmpFile.Close()
+		if err = WriteHTMLReport(tmpFile.Name(), report); err == nil {
+			if buf, err := ioutil.ReadFile(tmpFile.Name()); err == nil {
+				addFile("report.html", buf)
+			}
+		}
+		os.Remove(tmpFile.Name())
+	}
+
+	log.Infof(`handoff package for "%s" written to "%s"`, locale, zipName)
+	return true
+}
+
+// CmdHandoffImport implements handoff import sub command. It validates
+// the po file in a returned handoff package and merges its
+// translations back.
+func CmdHandoffImport(zipName string) bool {
+	r, err := zip.OpenReader(zipName)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	defer r.Close()
+
+	ret := true
+	imported := 0
+	for _, file := range r.File {
+		if filepath.Ext(file.Name) != ".po" {
+			continue
+		}
+		locale := filepath.Base(file.Name[:len(file.Name)-len(".po")])
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		buf, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		tmpFile, err := ioutil.TempFile("", "pofile")
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		tmpFile.Close()
+		if err = ioutil.WriteFile(tmpFile.Name(), buf, 0644); err != nil {
+			os.Remove(tmpFile.Name())
+			log.Error(err)
+			return false
+		}
+		if errs, ok := checkPoSyntax(tmpFile.Name()); !ok {
+			for _, err := range errs {
+				log.Error(err)
+			}
+			os.Remove(tmpFile.Name())
+			ret = false
+			continue
+		}
+		poFile := filepath.Join(PoDir, locale+".po")
+		if err = mergeTranslatedEntries(poFile, tmpFile.Name()); err != nil {
+			log.Error(err)
+			ret = false
+		} else {
+			log.Infof(`merged translations for "%s" from handoff package`, locale)
+			imported++
+		}
+		os.Remove(tmpFile.Name())
+	}
+	if imported == 0 {
+		log.Errorf(`no po files found in handoff package "%s"`, zipName)
+		return false
+	}
+	return ret
+}
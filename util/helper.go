@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/git-l10n/git-po-helper/data"
@@ -43,6 +47,64 @@ func IsDir(name string) bool {
 	return true
 }
 
+// listPoFiles returns the sorted ".po" files of the "po/" directory,
+// not descending into subdirectories.
+func listPoFiles() []string {
+	var poFiles []string
+	filepath.Walk(PoDir, func(path string, info os.FileInfo, err error) error {
+		if info == nil {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			if filepath.Ext(path) == ".po" {
+				poFiles = append(poFiles, path)
+			}
+			return nil
+		}
+		if path == PoDir {
+			return nil
+		}
+		return filepath.SkipDir
+	})
+	sort.Strings(poFiles)
+	return poFiles
+}
+
+// ReadInput reads contents of a file, and the file name "-" stands for
+// reading from stdin.
+func ReadInput(fileName string) ([]byte, error) {
+	if fileName == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(fileName)
+}
+
+// WriteOutput writes data to a file, and the file name "-" stands for
+// writing to stdout.
+func WriteOutput(fileName string, data []byte) error {
+	if fileName == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
+
+// CopyStdinToTempFile saves data from stdin into a temporary file, so
+// external tools which cannot read from pipes can work on it. The
+// caller is responsible for removing the temporary file.
+func CopyStdinToTempFile(pattern string) (string, error) {
+	tmpFile, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer tmpFile.Close()
+	if _, err = io.Copy(tmpFile, os.Stdin); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", err
+	}
+	return tmpFile.Name(), nil
+}
+
 // ShowExecError will try to return error message of stderr
 func ShowExecError(err error) {
 	if err == nil {
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"html/template"
+	"os"
+)
+
+// Template functions to lay out the inline SVG score chart.
+var htmlReportFuncs = template.FuncMap{
+	"svgHeight": func(results []AgentTestResult) int {
+		return len(results)*24 + 8
+	},
+	"svgTextY": func(i int) int {
+		return i*24 + 20
+	},
+	"svgBarY": func(i int) int {
+		return i*24 + 8
+	},
+	"svgBarWidth": func(score int) int {
+		return score * 5
+	},
+	"svgLabelX": func(score int) int {
+		return score*5 + 88
+	},
+	"svgBarColor": func(score int) string {
+		switch {
+		case score >= 80:
+			return "#4a4"
+		case score >= 50:
+			return "#fa0"
+		default:
+			return "#c44"
+		}
+	},
+}
+
+// htmlReportTemplate renders benchmark results as a standalone HTML
+// page with a result table and an inline SVG score chart.
+var htmlReportTemplate = template.Must(template.New("report").
+	Funcs(htmlReportFuncs).
+	Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>git-po-helper benchmark report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 0.3em 0.8em; text-align: left; }
+th { background: #eee; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<h1>git-po-helper benchmark report</h1>
+<h2>Results</h2>
+<table>
+<tr><th>Agent</th><th>Language</th><th>Score</th><th>Translated</th><th>Duration</th><th>Result</th></tr>
+{{range .}}<tr>
+<td>{{.Agent}}</td>
+<td>{{.Locale}}</td>
+<td>{{.Score}}</td>
+<td>{{.Translated}}/{{.Total}}</td>
+<td>{{printf "%.1fs" .DurationSec}}</td>
+<td>{{if .Error}}<span class="error">{{.Error}}</span>{{else}}ok{{end}}</td>
+</tr>
+{{end}}</table>
+<h2>Scores</h2>
+<svg width="640" height="{{svgHeight .}}" xmlns="http://www.w3.org/2000/svg">
+{{range $i, $r := .}}<text x="0" y="{{svgTextY $i}}" font-size="12">{{$r.Locale}}</text>
+<rect x="80" y="{{svgBarY $i}}" width="{{svgBarWidth $r.Score}}" height="16" fill="{{svgBarColor $r.Score}}"/>
+<text x="{{svgLabelX $r.Score}}" y="{{svgTextY $i}}" font-size="12">{{$r.Score}}</text>
+{{end}}</svg>
+</body>
+</html>
+`))
+
+// WriteHTMLReport renders benchmark results to a standalone HTML file.
+func WriteHTMLReport(fileName string, results []AgentTestResult) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return htmlReportTemplate.Execute(f, results)
+}
@@ -0,0 +1,160 @@
+package util
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// logFileStats holds the statistics of one parsed agent log file.
+type logFileStats struct {
+	Parser     string
+	Turns      int
+	Tokens     int
+	DurationMS int64
+}
+
+// cmdParseLogStats walks a directory of saved agent logs, parses each
+// through the stream parsers, and prints aggregate statistics. Files
+// in which no known agent output format is detected are skipped.
+func cmdParseLogStats(dir string) bool {
+	var stats []logFileStats
+
+	err := filepath.Walk(dir, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		fileStats, err := parseLogFileStats(fileName)
+		if err != nil {
+			log.Warnf(`fail to parse "%s": %s`, fileName, err)
+			return nil
+		}
+		if fileStats.Parser == "" {
+			log.Debugf(`skip "%s", no known agent output format detected`, fileName)
+			return nil
+		}
+		stats = append(stats, fileStats)
+		return nil
+	})
+	if err != nil {
+		log.Errorf(`fail to walk "%s": %s`, dir, err)
+		return false
+	}
+	if len(stats) == 0 {
+		log.Errorf(`no agent logs found in "%s"`, dir)
+		return false
+	}
+
+	var (
+		turns, tokens int
+		durations     []int64
+	)
+	for _, fileStats := range stats {
+		turns += fileStats.Turns
+		tokens += fileStats.Tokens
+		if fileStats.DurationMS > 0 {
+			durations = append(durations, fileStats.DurationMS)
+		}
+	}
+	fmt.Printf("Logs:         %d\n", len(stats))
+	fmt.Printf("Total tokens: %d\n", tokens)
+	fmt.Printf("Avg turns:    %.1f\n", float64(turns)/float64(len(stats)))
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		fmt.Printf("Duration:     p50 %.1fs, p90 %.1fs, max %.1fs\n",
+			float64(durationPercentile(durations, 50))/1000,
+			float64(durationPercentile(durations, 90))/1000,
+			float64(durations[len(durations)-1])/1000)
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "Agent\tLogs\tTurns\tTokens\tDuration")
+	for _, name := range logStatsParsers(stats) {
+		var (
+			logs, turns, tokens int
+			durationMS          int64
+		)
+		for _, fileStats := range stats {
+			if fileStats.Parser != name {
+				continue
+			}
+			logs++
+			turns += fileStats.Turns
+			tokens += fileStats.Tokens
+			durationMS += fileStats.DurationMS
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1fs\n",
+			name, logs, turns, tokens, float64(durationMS)/1000)
+	}
+	w.Flush()
+	return true
+}
+
+// parseLogFileStats parses one saved agent log file, transparently
+// decompressing gzip compressed logs, and counts turns, tokens and
+// duration from the event stream.
+func parseLogFileStats(fileName string) (logFileStats, error) {
+	var stats logFileStats
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return stats, err
+	}
+	defer f.Close()
+	var in io.Reader = f
+	if strings.HasSuffix(fileName, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return stats, err
+		}
+		defer gz.Close()
+		in = gz
+	}
+	display := newAgentOutputWriter(ioutil.Discard,
+		AgentParseOptions{
+			Level: AgentOutputQuiet,
+			Sink: func(event AgentEvent) {
+				if event.Type == AgentEventAssistant {
+					stats.Turns++
+				}
+				stats.Tokens += event.Tokens
+				stats.DurationMS += event.DurationMS
+			},
+		})
+	if _, err = io.Copy(display, in); err != nil {
+		return stats, err
+	}
+	display.Close()
+	stats.Parser = display.ParserName()
+	return stats, nil
+}
+
+// durationPercentile returns the given percentile of sorted durations.
+func durationPercentile(durations []int64, percent int) int64 {
+	idx := (len(durations) - 1) * percent / 100
+	return durations[idx]
+}
+
+// logStatsParsers returns the sorted parser names seen in the parsed
+// logs.
+func logStatsParsers(stats []logFileStats) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, fileStats := range stats {
+		if !seen[fileStats.Parser] {
+			seen[fileStats.Parser] = true
+			names = append(names, fileStats.Parser)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
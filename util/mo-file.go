@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/gorilla/i18n/gettext"
+)
+
+// moEntry is one (msgid, msgstr) pair decoded from a compiled mo file.
+type moEntry struct {
+	MsgID  string
+	MsgStr string
+}
+
+// readMoEntries compiles a po file into a temporary mo file using
+// msgfmt, and decodes the (msgid, msgstr) pairs of the mo file. A
+// plural entry expands into one pair per plural form: the first form
+// pairs with msgid, and the remaining forms pair with msgid_plural.
+// Returns false if no mo file can be generated or decoded.
+func readMoEntries(poFile string) ([]moEntry, []error, bool) {
+	var (
+		entries []moEntry
+		errs    []error
+	)
+
+	moFile, err := ioutil.TempFile("", "mofile")
+	if err != nil {
+		return nil, []error{err}, false
+	}
+	defer os.Remove(moFile.Name())
+	moFile.Close()
+	cmd := exec.Command("msgfmt",
+		"-o",
+		moFile.Name(),
+		poFile)
+	cmd.Dir = GitRootDir
+	err = cmd.Run()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("fail to compile %s: %s", poFile, err))
+	}
+	fi, err := os.Stat(moFile.Name())
+	if err != nil || fi.Size() == 0 {
+		errs = append(errs, fmt.Errorf("no mofile generated"))
+		return nil, errs, false
+	}
+
+	f, err := os.Open(moFile.Name())
+	if err != nil {
+		errs = append(errs, fmt.Errorf("cannot open %s: %s", moFile.Name(), err))
+		return nil, errs, false
+	}
+	defer f.Close()
+	iter := gettext.ReadMo(f)
+	for {
+		msg, err := iter.Next()
+		if err != nil {
+			if err != io.EOF {
+				errs = append(errs, fmt.Errorf("fail to iterator: %s", err))
+			}
+			break
+		}
+		if len(msg.StrPlural) == 0 {
+			entries = append(entries, moEntry{
+				MsgID:  string(msg.Id),
+				MsgStr: string(msg.Str),
+			})
+			continue
+		}
+		for i := range msg.StrPlural {
+			if i == 0 {
+				entries = append(entries, moEntry{
+					MsgID:  string(msg.Id),
+					MsgStr: string(msg.StrPlural[i]),
+				})
+			} else {
+				entries = append(entries, moEntry{
+					MsgID:  string(msg.IdPlural),
+					MsgStr: string(msg.StrPlural[i]),
+				})
+			}
+		}
+	}
+	return entries, errs, true
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// MsgSelectOptions holds the entry filters of the msg-select command.
+type MsgSelectOptions struct {
+	// Ranges is a comma separated list of entry index ranges, such as
+	// "3,101-200,500-".
+	Ranges string
+
+	// Untranslated only selects untranslated entries.
+	Untranslated bool
+
+	// Fuzzy only selects fuzzy entries.
+	Fuzzy bool
+
+	// Grep only selects entries whose msgid or msgctxt matches this
+	// regex.
+	Grep string
+}
+
+// MsgSelect returns the entries of a po file matching the given index
+// ranges and filters. The header entry is never part of the result.
+func MsgSelect(poFile *PoFile, opts MsgSelectOptions) ([]*PoEntry, error) {
+	type indexRange struct {
+		begin, end int
+	}
+	var (
+		ranges  []indexRange
+		pattern *regexp.Regexp
+		err     error
+	)
+
+	if opts.Ranges != "" {
+		for _, spec := range strings.Split(opts.Ranges, ",") {
+			begin, end, err := parseIndexRange(strings.TrimSpace(spec))
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, indexRange{begin: begin, end: end})
+		}
+	}
+	if opts.Grep != "" {
+		if pattern, err = regexp.Compile(opts.Grep); err != nil {
+			return nil, fmt.Errorf(`invalid --grep pattern: %s`, err)
+		}
+	}
+
+	var selected []*PoEntry
+	for i, entry := range poFile.Entries {
+		if len(ranges) > 0 {
+			matched := false
+			for _, r := range ranges {
+				if i >= r.begin && (r.end == 0 || i <= r.end) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if opts.Untranslated && entry.IsTranslated() {
+			continue
+		}
+		if opts.Fuzzy && !entry.IsFuzzy() {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(entry.MsgID) &&
+			!pattern.MatchString(entry.MsgCtxt) {
+			continue
+		}
+		selected = append(selected, entry)
+	}
+	return selected, nil
+}
+
+// CmdMsgSelect implements msg-select sub command. It writes selected
+// entries of a po file, together with its header, to stdout or the
+// file given by option "-o".
+func CmdMsgSelect(args ...string) bool {
+	if len(args) == 0 {
+		log.Errorf(`nothing to select, please provide a "XX.po" file`)
+		return false
+	}
+	poFile, err := LoadPoFile(args[0])
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	opts := MsgSelectOptions{
+		Ranges:       strings.Join(args[1:], ","),
+		Untranslated: FlagMsgSelectUntranslated(),
+		Fuzzy:        FlagMsgSelectFuzzy(),
+		Grep:         FlagMsgSelectGrep(),
+	}
+	selected, err := MsgSelect(poFile, opts)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if len(selected) == 0 {
+		log.Warnf(`no entries of "%s" match the selection`, args[0])
+	}
+
+	result := &PoFile{
+		Header:     poFile.Header,
+		LineEnding: poFile.LineEnding,
+	}
+	result.Entries = append(result.Entries, selected...)
+
+	output := FlagMsgSelectOutput()
+	if output == "" {
+		output = "-"
+	}
+	if err = result.Save(output); err != nil {
+		log.Error(err)
+		return false
+	}
+	return true
+}
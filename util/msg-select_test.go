@@ -0,0 +1,88 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestMsgSelectRanges(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	for _, tc := range []struct {
+		ranges string
+		count  int
+		first  string
+	}{
+		{"1", 1, "one file"},
+		{"1-2", 2, "one file"},
+		{"3-", 2, "untranslated entry"},
+		{"1,3-", 3, "one file"},
+		{"", 5, "could not open '%s'"},
+	} {
+		selected, err := MsgSelect(poFile, MsgSelectOptions{Ranges: tc.ranges})
+		if err != nil {
+			t.Errorf("fail to select ranges %q: %s", tc.ranges, err)
+			continue
+		}
+		if len(selected) != tc.count {
+			t.Errorf("ranges %q select %d entries, expect %d",
+				tc.ranges, len(selected), tc.count)
+			continue
+		}
+		if selected[0].MsgID != tc.first {
+			t.Errorf("ranges %q select %q first, expect %q",
+				tc.ranges, selected[0].MsgID, tc.first)
+		}
+	}
+	if _, err := MsgSelect(poFile, MsgSelectOptions{Ranges: "x-y"}); err == nil {
+		t.Error("no error for invalid range")
+	}
+}
+
+func TestMsgSelectFilters(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+
+	selected, err := MsgSelect(poFile, MsgSelectOptions{Untranslated: true})
+	if err != nil {
+		t.Fatalf("fail to select untranslated entries: %s", err)
+	}
+	if len(selected) != 1 || selected[0].MsgID != "untranslated entry" {
+		t.Errorf("bad untranslated selection: %+v", selected)
+	}
+
+	selected, err = MsgSelect(poFile, MsgSelectOptions{Fuzzy: true})
+	if err != nil {
+		t.Fatalf("fail to select fuzzy entries: %s", err)
+	}
+	if len(selected) != 1 || selected[0].MsgID != "fuzzy entry" {
+		t.Errorf("bad fuzzy selection: %+v", selected)
+	}
+
+	selected, err = MsgSelect(poFile, MsgSelectOptions{Grep: "file"})
+	if err != nil {
+		t.Fatalf("fail to select entries by grep: %s", err)
+	}
+	if len(selected) != 1 || selected[0].MsgID != "one file" {
+		t.Errorf("bad grep selection: %+v", selected)
+	}
+
+	selected, err = MsgSelect(poFile, MsgSelectOptions{
+		Ranges: "2-",
+		Grep:   "entry",
+		Fuzzy:  true,
+	})
+	if err != nil {
+		t.Fatalf("fail to combine filters: %s", err)
+	}
+	if len(selected) != 1 || selected[0].MsgID != "fuzzy entry" {
+		t.Errorf("bad combined selection: %+v", selected)
+	}
+
+	if _, err = MsgSelect(poFile, MsgSelectOptions{Grep: "("}); err == nil {
+		t.Error("no error for invalid grep pattern")
+	}
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// duplicatedPunctRunes are punctuation characters which machine
+	// translation tends to duplicate, such as "!!" or "，，". Periods
+	// are excluded, "..." is a legitimate ellipsis.
+	duplicatedPunctRunes = ",;:!?、，；：！？"
+
+	// aiLeakagePhrases are phrases leaked from the agent itself into
+	// msgstr, instead of a translation.
+	aiLeakagePhrases = []string{
+		"as an ai",
+		"as a language model",
+		"i cannot translate",
+		"i'm sorry",
+		"here is the translation",
+	}
+
+	// promptEchoFragments are fragments of the prompt or the po file
+	// syntax echoed into msgstr.
+	promptEchoFragments = []string{
+		`msgid "`,
+		`msgstr "`,
+		"translate the following",
+		"{source}",
+		"{lang}",
+	}
+)
+
+// mtArtifactRule is one heuristic check for machine-translation
+// artifacts in a translated entry. It returns the explanation of the
+// detected artifact, or an empty string.
+type mtArtifactRule struct {
+	Name  string
+	Check func(msgID, msgStr string) string
+}
+
+// mtArtifactRules is the rule pack for detecting typical machine
+// translation artifacts in msgstr.
+var mtArtifactRules = []mtArtifactRule{
+	{
+		Name: "untranslated-trailing-english",
+		Check: func(msgID, msgStr string) string {
+			tail := lastSentence(msgID)
+			if len(tail) < 10 || msgStr == msgID {
+				return ""
+			}
+			if strings.HasSuffix(strings.TrimSpace(msgStr), tail) {
+				return fmt.Sprintf(
+					"trailing English sentence left untranslated: %q", tail)
+			}
+			return ""
+		},
+	},
+	{
+		Name: "duplicated-punctuation",
+		Check: func(msgID, msgStr string) string {
+			match := duplicatedPunct(msgStr)
+			if match == "" || strings.Contains(msgID, match) {
+				return ""
+			}
+			return fmt.Sprintf("duplicated punctuation: %q", match)
+		},
+	},
+	{
+		Name: "ai-leakage",
+		Check: func(msgID, msgStr string) string {
+			lower := strings.ToLower(msgStr)
+			for _, phrase := range aiLeakagePhrases {
+				if strings.Contains(lower, phrase) &&
+					!strings.Contains(strings.ToLower(msgID), phrase) {
+					return fmt.Sprintf("agent chatter leaked into translation: %q", phrase)
+				}
+			}
+			return ""
+		},
+	},
+	{
+		Name: "prompt-echo",
+		Check: func(msgID, msgStr string) string {
+			lower := strings.ToLower(msgStr)
+			for _, fragment := range promptEchoFragments {
+				if strings.Contains(lower, fragment) &&
+					!strings.Contains(strings.ToLower(msgID), fragment) {
+					return fmt.Sprintf("prompt fragment echoed into translation: %q", fragment)
+				}
+			}
+			return ""
+		},
+	},
+}
+
+// duplicatedPunct returns the first run of duplicated punctuation in a
+// message, or an empty string. The regexp package has no backreference
+// support, so scan runes directly.
+func duplicatedPunct(text string) string {
+	var last rune
+	for _, c := range text {
+		if c == last && strings.ContainsRune(duplicatedPunctRunes, c) {
+			return string([]rune{c, c})
+		}
+		last = c
+	}
+	return ""
+}
+
+// lastSentence returns the trailing sentence of a message, with
+// surrounding whitespace removed.
+func lastSentence(text string) string {
+	text = strings.TrimSpace(text)
+	for _, sep := range []string{". ", "! ", "? ", "\n"} {
+		if idx := strings.LastIndex(text, sep); idx >= 0 {
+			text = text[idx+len(sep):]
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// DetectMTArtifacts runs the machine-translation artifact rule pack on
+// all translated entries of a po file, and reports detected artifacts
+// as review issues.
+func DetectMTArtifacts(fileName string) ([]ReviewIssue, error) {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var issues []ReviewIssue
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete || entry.MsgID == "" || !entry.IsTranslated() {
+			continue
+		}
+		for _, rule := range mtArtifactRules {
+			explanation := rule.Check(entry.MsgID, entry.MsgStr)
+			if explanation == "" {
+				continue
+			}
+			issues = append(issues, ReviewIssue{
+				MsgID:       entry.MsgID,
+				MsgStr:      entry.MsgStr,
+				Explanation: fmt.Sprintf("%s: %s", rule.Name, explanation),
+				Severity:    "warning",
+			})
+		}
+	}
+	return issues, nil
+}
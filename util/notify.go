@@ -0,0 +1,104 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NotifySummary is the JSON payload posted to the notification webhook
+// when an agent run or benchmark finishes.
+type NotifySummary struct {
+	Action      string  `json:"action"`
+	Agent       string  `json:"agent,omitempty"`
+	Files       string  `json:"files,omitempty"`
+	OK          bool    `json:"ok"`
+	Score       int     `json:"score,omitempty"`
+	Issues      int     `json:"issues,omitempty"`
+	DurationSec float64 `json:"duration_sec"`
+	CostUSD     float64 `json:"cost_usd,omitempty"`
+}
+
+// notifyTimeout bounds the webhook request, so a dead endpoint cannot
+// hang the end of a run.
+const notifyTimeout = 10 * time.Second
+
+// notifyText renders a one-line human readable summary for chat
+// oriented webhooks.
+func (v *NotifySummary) notifyText() string {
+	status := "OK"
+	if !v.OK {
+		status = "FAILED"
+	}
+	text := fmt.Sprintf("git-po-helper %s %s: %s (%.1fs)",
+		v.Action, v.Files, status, v.DurationSec)
+	if v.Score > 0 {
+		text += fmt.Sprintf(", score %d", v.Score)
+	}
+	if v.Issues > 0 {
+		text += fmt.Sprintf(", %d issues", v.Issues)
+	}
+	return text
+}
+
+// Notify posts a run summary to the webhook of the "notify.url" config
+// variable, if any. The "notify.kind" config variable selects the
+// payload format: "slack", "matrix", or "generic" (the default).
+// Notification failures are logged, but never fail the run itself.
+func Notify(summary *NotifySummary) {
+	url := viper.GetString("notify.url")
+	if url == "" {
+		return
+	}
+	var payload interface{}
+	switch kind := viper.GetString("notify.kind"); kind {
+	case "slack":
+		payload = map[string]string{"text": summary.notifyText()}
+	case "matrix":
+		payload = map[string]string{
+			"msgtype": "m.text",
+			"body":    summary.notifyText(),
+		}
+	case "", "generic":
+		payload = summary
+	default:
+		log.Warnf(`unknown notify kind "%s", expect "slack", "matrix" or "generic"`, kind)
+		return
+	}
+	buf, err := json.Marshal(payload)
+	if err != nil {
+		log.Warnf("fail to marshal notification: %s", err)
+		return
+	}
+	client := &http.Client{Timeout: notifyTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(buf))
+	if err != nil {
+		log.Warnf("fail to post notification: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Warnf("notification webhook answered: %s", resp.Status)
+	}
+}
+
+// notifyAgentRun posts a summary of an agent-run command, naming the
+// files the agent worked on.
+func notifyAgentRun(action string, agent *Agent, files []string, ok bool, duration time.Duration) {
+	summary := NotifySummary{
+		Action:      action,
+		Files:       strings.Join(files, " "),
+		OK:          ok,
+		DurationSec: duration.Seconds(),
+	}
+	if agent != nil {
+		summary.Agent = agent.Name
+	}
+	Notify(&summary)
+}
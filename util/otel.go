@@ -0,0 +1,238 @@
+package util
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/git-l10n/git-po-helper/version"
+)
+
+// Telemetry spans and counters for agent executions, exported in the
+// OTLP/HTTP JSON encoding so any OpenTelemetry collector can receive
+// them. The encoding is hand-rolled for the tiny subset used here, to
+// keep the module free of the OpenTelemetry SDK dependency.
+
+// otelTimeout bounds an OTLP export, so a slow collector cannot stall
+// the command.
+const otelTimeout = 10 * time.Second
+
+// otelSpan is one finished span, encoded into the OTLP JSON document on
+// flush.
+type otelSpan struct {
+	spanID   string
+	parentID string
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    map[string]interface{}
+}
+
+var (
+	otelMu       sync.Mutex
+	otelTraceID  string
+	otelSpans    []*otelSpan
+	otelCounters = make(map[string]int64)
+)
+
+// otelEndpoint returns the base URL of the OTLP/HTTP collector, set by
+// config variable "otel.endpoint" or the standard environment variable
+// OTEL_EXPORTER_OTLP_ENDPOINT. Telemetry is disabled when unset.
+func otelEndpoint() string {
+	return strings.TrimSuffix(viper.GetString("otel.endpoint"), "/")
+}
+
+// otelRandomID returns a random lowercase hex ID of the given byte
+// size, for trace IDs (16 bytes) and span IDs (8 bytes).
+func otelRandomID(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return strings.Repeat("0", size*2)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// startSpan records the start of a telemetry span, and returns a
+// closure ending it. Attributes can be attached on both ends:
+//
+//	endSpan := startSpan("agent/invoke", "agent", agent.Name)
+//	defer func() { endSpan("error", err != nil) }()
+func startSpan(name string, attrs ...interface{}) func(attrs ...interface{}) {
+	if otelEndpoint() == "" {
+		return func(attrs ...interface{}) {}
+	}
+	otelMu.Lock()
+	if otelTraceID == "" {
+		otelTraceID = otelRandomID(16)
+	}
+	span := &otelSpan{
+		spanID: otelRandomID(8),
+		name:   name,
+		start:  time.Now(),
+		attrs:  otelAttrMap(attrs),
+	}
+	otelMu.Unlock()
+	return func(attrs ...interface{}) {
+		span.end = time.Now()
+		for key, value := range otelAttrMap(attrs) {
+			span.attrs[key] = value
+		}
+		otelMu.Lock()
+		otelSpans = append(otelSpans, span)
+		otelMu.Unlock()
+	}
+}
+
+// addCounter increments a telemetry counter, such as the number of
+// agent invocations or the tokens reported by an agent. Counters are
+// also shown on the TUI dashboard.
+func addCounter(name string, delta int64) {
+	tuiAddCounter(name, delta)
+	if otelEndpoint() == "" {
+		return
+	}
+	otelMu.Lock()
+	otelCounters[name] += delta
+	otelMu.Unlock()
+}
+
+// otelAttrMap turns a flat key, value, key, value list into a map.
+func otelAttrMap(attrs []interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	for i := 0; i+1 < len(attrs); i += 2 {
+		key, ok := attrs[i].(string)
+		if !ok {
+			continue
+		}
+		m[key] = attrs[i+1]
+	}
+	return m
+}
+
+// otelAttrs encodes an attribute map as the OTLP JSON attribute list.
+func otelAttrs(attrs map[string]interface{}) []map[string]interface{} {
+	var list []map[string]interface{}
+	for key, value := range attrs {
+		var encoded map[string]interface{}
+		switch v := value.(type) {
+		case bool:
+			encoded = map[string]interface{}{"boolValue": v}
+		case int:
+			encoded = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int64:
+			encoded = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		default:
+			encoded = map[string]interface{}{"stringValue": fmt.Sprintf("%v", v)}
+		}
+		list = append(list, map[string]interface{}{"key": key, "value": encoded})
+	}
+	return list
+}
+
+// otelResource is the OTLP resource shared by traces and metrics,
+// naming the service and the run ID of the invocation.
+func otelResource() map[string]interface{} {
+	return map[string]interface{}{
+		"attributes": otelAttrs(map[string]interface{}{
+			"service.name":    "git-po-helper",
+			"service.version": version.Version,
+			"run.id":          RunID(),
+		}),
+	}
+}
+
+// otelPost sends one OTLP JSON document to the collector. Export
+// problems are logged as warnings, and never fail the command.
+func otelPost(path string, payload interface{}) {
+	buf, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	client := &http.Client{Timeout: otelTimeout}
+	resp, err := client.Post(otelEndpoint()+path, "application/json",
+		bytes.NewReader(buf))
+	if err != nil {
+		log.Warnf("fail to export telemetry: %s", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Warnf("fail to export telemetry: collector returned %s", resp.Status)
+	}
+}
+
+// flushTelemetry exports the recorded spans and counters to the OTLP
+// collector, and is called once when an agent-run command finishes.
+func flushTelemetry() {
+	if otelEndpoint() == "" {
+		return
+	}
+	otelMu.Lock()
+	spans := otelSpans
+	counters := otelCounters
+	otelSpans = nil
+	otelCounters = make(map[string]int64)
+	otelMu.Unlock()
+
+	if len(spans) > 0 {
+		var encoded []map[string]interface{}
+		for _, span := range spans {
+			encoded = append(encoded, map[string]interface{}{
+				"traceId":           otelTraceID,
+				"spanId":            span.spanID,
+				"parentSpanId":      span.parentID,
+				"name":              span.name,
+				"kind":              1,
+				"startTimeUnixNano": strconv.FormatInt(span.start.UnixNano(), 10),
+				"endTimeUnixNano":   strconv.FormatInt(span.end.UnixNano(), 10),
+				"attributes":        otelAttrs(span.attrs),
+			})
+		}
+		otelPost("/v1/traces", map[string]interface{}{
+			"resourceSpans": []map[string]interface{}{{
+				"resource": otelResource(),
+				"scopeSpans": []map[string]interface{}{{
+					"scope": map[string]interface{}{"name": "git-po-helper"},
+					"spans": encoded,
+				}},
+			}},
+		})
+	}
+
+	if len(counters) > 0 {
+		now := strconv.FormatInt(time.Now().UnixNano(), 10)
+		var metrics []map[string]interface{}
+		for name, value := range counters {
+			metrics = append(metrics, map[string]interface{}{
+				"name": name,
+				"sum": map[string]interface{}{
+					"aggregationTemporality": 1,
+					"isMonotonic":            true,
+					"dataPoints": []map[string]interface{}{{
+						"asInt":        strconv.FormatInt(value, 10),
+						"timeUnixNano": now,
+					}},
+				},
+			})
+		}
+		otelPost("/v1/metrics", map[string]interface{}{
+			"resourceMetrics": []map[string]interface{}{{
+				"resource": otelResource(),
+				"scopeMetrics": []map[string]interface{}{{
+					"scope":   map[string]interface{}{"name": "git-po-helper"},
+					"metrics": metrics,
+				}},
+			}},
+		})
+	}
+}
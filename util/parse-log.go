@@ -0,0 +1,106 @@
+package util
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CmdParseLog implements parse-log sub command, rendering saved agent
+// output logs through the stream parsers, like the realtime display of
+// agent-run. Truncated files fail the command, unless option
+// "--tolerant" downgrades stream problems to warnings. Option
+// "--format" forces the parser of one agent kind instead of
+// auto-detection, and option "--export" also writes a readable
+// Markdown or HTML transcript of the parsed logs. Option "--stats"
+// aggregates statistics over a directory of saved logs instead of
+// rendering them.
+func CmdParseLog(args ...string) bool {
+	var (
+		ret         = true
+		transcripts []*agentTranscript
+	)
+
+	if dir := FlagParseLogStats(); dir != "" {
+		return cmdParseLogStats(dir)
+	}
+	if len(args) == 0 {
+		log.Errorf("nothing to parse, please provide agent log files")
+		return false
+	}
+	format := FlagParseLogFormat()
+	if format != "" && format != "auto" {
+		known := false
+		var names []string
+		for _, parser := range agentStreamParsers() {
+			names = append(names, parser.Name())
+			if parser.Name() == format {
+				known = true
+			}
+		}
+		if !known {
+			log.Errorf(`unknown format "%s", available formats are: auto, %s`,
+				format, strings.Join(names, ", "))
+			return false
+		}
+	}
+	for _, fileName := range args {
+		var (
+			in  io.ReadCloser
+			err error
+		)
+		if fileName == "-" {
+			in = os.Stdin
+		} else if in, err = os.Open(fileName); err != nil {
+			log.Errorf(`fail to open "%s": %s`, fileName, err)
+			ret = false
+			continue
+		}
+		opts := AgentParseOptions{
+			Level:  FlagParseLogOutput(),
+			Format: format,
+		}
+		transcript := &agentTranscript{FileName: fileName}
+		if FlagParseLogExport() != "" {
+			opts.Sink = func(event AgentEvent) {
+				transcript.Events = append(transcript.Events, event)
+			}
+		}
+		display := newAgentOutputWriter(os.Stdout, opts)
+		_, err = io.Copy(display, in)
+		display.Close()
+		if fileName != "-" {
+			in.Close()
+		}
+		if err != nil {
+			log.Errorf(`fail to read "%s": %s`, fileName, err)
+			ret = false
+			continue
+		}
+		if name := display.ParserName(); name != "" {
+			log.Infof(`%s: parsed as "%s" agent output`, fileName, name)
+		} else {
+			log.Warnf(`%s: no known agent output format detected`, fileName)
+		}
+		if streamErr := display.Err(); streamErr != nil {
+			transcript.Err = streamErr.Error()
+			if FlagParseLogTolerant() {
+				log.Warnf(`%s: %s`, fileName, streamErr)
+			} else {
+				log.Errorf(`%s: %s`, fileName, streamErr)
+				ret = false
+			}
+		}
+		transcript.Parser = display.ParserName()
+		transcripts = append(transcripts, transcript)
+	}
+	if exportFile := FlagParseLogExport(); exportFile != "" {
+		if err := exportTranscripts(exportFile, transcripts); err != nil {
+			log.Errorf("fail to export transcript: %s", err)
+			ret = false
+		}
+	}
+	return ret
+}
@@ -0,0 +1,114 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PoEscape escapes a string as a double-quoted string literal of a po
+// file, using C-style escape sequences.
+func PoEscape(s string) string {
+	var sb strings.Builder
+
+	for _, c := range []byte(s) {
+		switch c {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\a':
+			sb.WriteString(`\a`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\v':
+			sb.WriteString(`\v`)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// PoUnescape unescapes a double-quoted string literal of a po file.
+// It is the inverse of PoEscape, and also accepts octal and hexadecimal
+// escape sequences as gettext does.
+func PoUnescape(s string) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", fmt.Errorf("incomplete escape sequence at end of %q", s)
+		}
+		switch s[i] {
+		case '\\':
+			sb.WriteByte('\\')
+		case '"':
+			sb.WriteByte('"')
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'a':
+			sb.WriteByte('\a')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'v':
+			sb.WriteByte('\v')
+		case 'x':
+			var value, n int
+			for ; n < 2 && i+1 < len(s) && isHexDigit(s[i+1]); n++ {
+				i++
+				value = value*16 + hexDigitValue(s[i])
+			}
+			if n == 0 {
+				return "", fmt.Errorf("invalid hexadecimal escape sequence in %q", s)
+			}
+			sb.WriteByte(byte(value))
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			value := int(s[i] - '0')
+			for n := 1; n < 3 && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '7'; n++ {
+				i++
+				value = value*8 + int(s[i]-'0')
+			}
+			sb.WriteByte(byte(value))
+		default:
+			return "", fmt.Errorf("invalid escape sequence \"\\%c\" in %q", s[i], s)
+		}
+	}
+	return sb.String(), nil
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') ||
+		(c >= 'a' && c <= 'f') ||
+		(c >= 'A' && c <= 'F')
+}
+
+func hexDigitValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	default:
+		return int(c-'A') + 10
+	}
+}
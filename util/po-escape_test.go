@@ -0,0 +1,78 @@
+package util
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPoEscape(t *testing.T) {
+	for _, tc := range []struct {
+		Raw     string
+		Escaped string
+	}{
+		{"", ""},
+		{"hello, world", "hello, world"},
+		{"tab\there", `tab\there`},
+		{"new\nline", `new\nline`},
+		{"return\r\n", `return\r\n`},
+		{`back\slash`, `back\\slash`},
+		{`double\\backslash`, `double\\\\backslash`},
+		{`say "hello"`, `say \"hello\"`},
+		{"\a\b\f\v", `\a\b\f\v`},
+	} {
+		if escaped := PoEscape(tc.Raw); escaped != tc.Escaped {
+			t.Errorf("PoEscape(%q) = %q, expect %q", tc.Raw, escaped, tc.Escaped)
+		}
+	}
+}
+
+func TestPoUnescape(t *testing.T) {
+	for _, tc := range []struct {
+		Escaped string
+		Raw     string
+	}{
+		{`\101\102\103`, "ABC"},
+		{`\x41\x42\x43`, "ABC"},
+		{`\0`, "\000"},
+		{`\r\"`, "\r\""},
+		{`\\\\`, `\\`},
+		{`\\r`, `\r`},
+	} {
+		raw, err := PoUnescape(tc.Escaped)
+		if err != nil {
+			t.Errorf("PoUnescape(%q) failed: %s", tc.Escaped, err)
+		} else if raw != tc.Raw {
+			t.Errorf("PoUnescape(%q) = %q, expect %q", tc.Escaped, raw, tc.Raw)
+		}
+	}
+	for _, escaped := range []string{`\`, `\q`, `\x`, `abc\`} {
+		if raw, err := PoUnescape(escaped); err == nil {
+			t.Errorf("PoUnescape(%q) = %q, expect error", escaped, raw)
+		}
+	}
+}
+
+func TestPoEscapeRoundTrip(t *testing.T) {
+	// Characters which stress the escaping: backslashes, quotes,
+	// control characters, and multi-byte runes.
+	alphabet := []rune{
+		'\\', '"', '\n', '\t', '\r', '\a', '\b', '\f', '\v',
+		'a', 'z', '0', ' ', '%', '中', '文', 'é', '€',
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		runes := make([]rune, rnd.Intn(32))
+		for j := range runes {
+			runes[j] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		raw := string(runes)
+		escaped := PoEscape(raw)
+		back, err := PoUnescape(escaped)
+		if err != nil {
+			t.Fatalf("PoUnescape(PoEscape(%q)) failed: %s", raw, err)
+		}
+		if back != raw {
+			t.Fatalf("PoUnescape(PoEscape(%q)) = %q, lost in round trip", raw, back)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PoEntry is one entry of a po file.
+type PoEntry struct {
+	// Comments holds the comment lines of the entry, each with its
+	// leading "#" marker.
+	Comments []string
+
+	MsgCtxt      string
+	MsgID        string
+	MsgIDPlural  string
+	MsgStr       string
+	MsgStrPlural []string
+
+	// Obsolete marks an entry commented out with the "#~" marker.
+	Obsolete bool
+
+	hasCtxt   bool
+	hasPlural bool
+}
+
+// Key returns the identity of the entry, the msgctxt and msgid joined
+// the way gettext tools do, so contexted messages are not conflated
+// with plain messages of the same msgid.
+func (v *PoEntry) Key() string {
+	return poEntryKey(v.MsgCtxt, v.MsgID)
+}
+
+// poEntryKey joins a msgctxt and msgid with the EOT separator used by
+// gettext tools.
+func poEntryKey(msgctxt, msgid string) string {
+	if msgctxt == "" {
+		return msgid
+	}
+	return msgctxt + "\x04" + msgid
+}
+
+// IsFuzzy indicates the entry is marked with the fuzzy flag.
+func (v *PoEntry) IsFuzzy() bool {
+	for _, comment := range v.Comments {
+		if !strings.HasPrefix(comment, "#,") {
+			continue
+		}
+		for _, flag := range strings.Split(comment[2:], ",") {
+			if strings.TrimSpace(flag) == "fuzzy" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsTranslated indicates the entry has a non-empty translation.
+func (v *PoEntry) IsTranslated() bool {
+	if v.hasPlural {
+		if len(v.MsgStrPlural) == 0 {
+			return false
+		}
+		for _, str := range v.MsgStrPlural {
+			if str == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return v.MsgStr != ""
+}
+
+// PoFile is a po file parsed into header and entries.
+type PoFile struct {
+	FileName string
+	Header   *PoEntry
+	Entries  []*PoEntry
+
+	// LineEnding is used when writing the po file, and defaults to
+	// "\n" if empty.
+	LineEnding string
+}
+
+// LoadPoFile reads and parses a po file. The file name "-" stands for
+// reading from stdin.
+func LoadPoFile(fileName string) (*PoFile, error) {
+	buf, err := ReadInput(fileName)
+	if err != nil {
+		return nil, err
+	}
+	poFile, err := ParsePoData(buf, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return poFile, nil
+}
+
+// ParsePoData parses the contents of a po file. The reader is line
+// ending agnostic: an UTF-8 BOM and CRLF line endings are accepted
+// and stripped.
+func ParsePoData(data []byte, fileName string) (*PoFile, error) {
+	var (
+		poFile = PoFile{FileName: fileName}
+		entry  *PoEntry
+		field  *string
+		lineNo = 0
+	)
+
+	// Strip UTF-8 BOM, which may come from editors on Windows.
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+
+	syntaxErr := func(line string) error {
+		return fmt.Errorf("%s:%d: syntax error: %s", fileName, lineNo, line)
+	}
+	endEntry := func() {
+		if entry == nil {
+			return
+		}
+		if poFile.Header == nil && entry.MsgID == "" && !entry.hasCtxt && !entry.Obsolete {
+			poFile.Header = entry
+		} else {
+			poFile.Entries = append(poFile.Entries, entry)
+		}
+		entry = nil
+		field = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		lineNo++
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			endEntry()
+			continue
+		}
+		obsolete := false
+		if strings.HasPrefix(line, "#~") {
+			obsolete = true
+			line = strings.TrimSpace(line[2:])
+			if line == "" {
+				continue
+			}
+		}
+		if entry == nil {
+			entry = &PoEntry{}
+		}
+		if obsolete {
+			entry.Obsolete = true
+		}
+		if strings.HasPrefix(line, "#") && !obsolete {
+			if field != nil {
+				// Comment after string fields starts a new entry.
+				endEntry()
+				entry = &PoEntry{}
+			}
+			entry.Comments = append(entry.Comments, line)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "msgctxt "):
+			if field != nil {
+				endEntry()
+				entry = &PoEntry{Obsolete: obsolete}
+			}
+			entry.hasCtxt = true
+			field = &entry.MsgCtxt
+			line = line[len("msgctxt "):]
+		case strings.HasPrefix(line, "msgid_plural "):
+			entry.hasPlural = true
+			field = &entry.MsgIDPlural
+			line = line[len("msgid_plural "):]
+		case strings.HasPrefix(line, "msgid "):
+			// A msgid after the msgctxt continues the same entry.
+			if field != nil && field != &entry.MsgCtxt {
+				endEntry()
+				entry = &PoEntry{Obsolete: obsolete}
+			}
+			field = &entry.MsgID
+			line = line[len("msgid "):]
+		case strings.HasPrefix(line, "msgstr["):
+			idx := strings.Index(line, "]")
+			if idx < 0 {
+				return nil, syntaxErr(line)
+			}
+			entry.MsgStrPlural = append(entry.MsgStrPlural, "")
+			field = &entry.MsgStrPlural[len(entry.MsgStrPlural)-1]
+			line = strings.TrimSpace(line[idx+1:])
+		case strings.HasPrefix(line, "msgstr "):
+			field = &entry.MsgStr
+			line = line[len("msgstr "):]
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, syntaxErr(line)
+			}
+		default:
+			return nil, syntaxErr(line)
+		}
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, `"`) || !strings.HasSuffix(line, `"`) || len(line) < 2 {
+			return nil, syntaxErr(line)
+		}
+		str, err := PoUnescape(line[1 : len(line)-1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", fileName, lineNo, err)
+		}
+		*field += str
+	}
+	endEntry()
+	if poFile.Header == nil {
+		return nil, fmt.Errorf(`fail to parse "%s", no header found`, fileName)
+	}
+	return &poFile, nil
+}
+
+// HeaderField returns a field of the po file header, such as
+// "Plural-Forms", or an empty string if the field is missing.
+func (v *PoFile) HeaderField(name string) string {
+	if v.Header == nil {
+		return ""
+	}
+	for _, line := range strings.Split(v.Header.MsgStr, "\n") {
+		if strings.HasPrefix(line, name+":") {
+			return strings.TrimSpace(strings.TrimPrefix(line, name+":"))
+		}
+	}
+	return ""
+}
+
+// SetHeaderField updates a field of the po file header in place, or
+// appends the field if it is missing. Setting a field on a po file
+// without a header is a no-op.
+func (v *PoFile) SetHeaderField(name, value string) {
+	if v.Header == nil {
+		return
+	}
+	var (
+		lines = strings.SplitAfter(v.Header.MsgStr, "\n")
+		found = false
+	)
+	for i, line := range lines {
+		if strings.HasPrefix(line, name+":") {
+			lines[i] = fmt.Sprintf("%s: %s\n", name, value)
+			found = true
+			break
+		}
+	}
+	if !found {
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s\n", name, value))
+	}
+	v.Header.MsgStr = strings.Join(lines, "")
+}
+
+// Data serializes the po file. Line endings are normalized to the
+// LineEnding field of the po file, which defaults to LF.
+func (v *PoFile) Data() []byte {
+	var (
+		sb  strings.Builder
+		eol = v.LineEnding
+	)
+
+	if eol == "" {
+		eol = "\n"
+	}
+	writeEntry := func(entry *PoEntry) {
+		prefix := ""
+		if entry.Obsolete {
+			prefix = "#~ "
+		}
+		for _, comment := range entry.Comments {
+			sb.WriteString(comment)
+			sb.WriteString(eol)
+		}
+		if entry.hasCtxt {
+			writePoString(&sb, prefix, "msgctxt", entry.MsgCtxt, eol)
+		}
+		writePoString(&sb, prefix, "msgid", entry.MsgID, eol)
+		if entry.hasPlural {
+			writePoString(&sb, prefix, "msgid_plural", entry.MsgIDPlural, eol)
+		}
+		if len(entry.MsgStrPlural) > 0 {
+			for i, str := range entry.MsgStrPlural {
+				writePoString(&sb, prefix, fmt.Sprintf("msgstr[%d]", i), str, eol)
+			}
+		} else {
+			writePoString(&sb, prefix, "msgstr", entry.MsgStr, eol)
+		}
+	}
+	if v.Header != nil {
+		writeEntry(v.Header)
+	}
+	for _, entry := range v.Entries {
+		sb.WriteString(eol)
+		writeEntry(entry)
+	}
+	return []byte(sb.String())
+}
+
+// Save serializes and writes the po file. The file name "-" stands for
+// writing to stdout.
+func (v *PoFile) Save(fileName string) error {
+	return WriteOutput(fileName, v.Data())
+}
+
+// writePoString writes one string field of a po entry, splitting
+// multi-line strings the way gettext tools do.
+func writePoString(sb *strings.Builder, prefix, keyword, str, eol string) {
+	lines := strings.SplitAfter(str, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		fmt.Fprintf(sb, "%s%s \"%s\"%s", prefix, keyword, PoEscape(str), eol)
+		return
+	}
+	fmt.Fprintf(sb, "%s%s \"\"%s", prefix, keyword, eol)
+	for _, line := range lines {
+		fmt.Fprintf(sb, "%s\"%s\"%s", prefix, PoEscape(line), eol)
+	}
+}
@@ -0,0 +1,144 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+var poSample = `# Sample po file.
+msgid ""
+msgstr ""
+"Project-Id-Version: git-po-helper\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: builtin/add.c:100
+#, c-format
+msgid "could not open '%s'"
+msgstr "无法打开 '%s'"
+
+msgid "one file"
+msgid_plural "%d files"
+msgstr[0] "%d 个文件"
+
+#, fuzzy
+msgid "fuzzy entry"
+msgstr "模糊条目"
+
+msgid "untranslated entry"
+msgstr ""
+
+#~ msgid "obsolete entry"
+#~ msgstr "过时条目"
+`
+
+func TestParsePoData(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "test.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	if poFile.Header == nil {
+		t.Fatal("no header parsed")
+	}
+	if !strings.Contains(poFile.Header.MsgStr, "Project-Id-Version: git-po-helper\n") {
+		t.Errorf("bad header msgstr: %q", poFile.Header.MsgStr)
+	}
+	if len(poFile.Entries) != 5 {
+		t.Fatalf("parsed %d entries, expect 5", len(poFile.Entries))
+	}
+	if poFile.Entries[0].MsgID != "could not open '%s'" {
+		t.Errorf("bad msgid: %q", poFile.Entries[0].MsgID)
+	}
+	if poFile.Entries[1].MsgIDPlural != "%d files" {
+		t.Errorf("bad msgid_plural: %q", poFile.Entries[1].MsgIDPlural)
+	}
+	if !poFile.Entries[2].IsFuzzy() {
+		t.Error("fuzzy entry not detected")
+	}
+	if poFile.Entries[3].IsTranslated() {
+		t.Error("untranslated entry reported as translated")
+	}
+	if !poFile.Entries[4].Obsolete {
+		t.Error("obsolete entry not detected")
+	}
+}
+
+func TestParsePoDataWithBOMAndCRLF(t *testing.T) {
+	data := "\xef\xbb\xbf" + strings.Replace(poSample, "\n", "\r\n", -1)
+	poFile, err := ParsePoData([]byte(data), "test.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data with BOM and CRLF: %s", err)
+	}
+	plain, err := ParsePoData([]byte(poSample), "test.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	if string(poFile.Data()) != string(plain.Data()) {
+		t.Errorf("BOM/CRLF input not normalized:\n%s", string(poFile.Data()))
+	}
+	if strings.Contains(string(poFile.Data()), "\r") {
+		t.Error("normalized output still contains CR")
+	}
+}
+
+func TestPoDataRoundTrip(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "test.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	data := poFile.Data()
+	again, err := ParsePoData(data, "test.po")
+	if err != nil {
+		t.Fatalf("fail to reparse serialized po data: %s", err)
+	}
+	if string(again.Data()) != string(data) {
+		t.Errorf("po data not stable in round trip:\n--- first\n%s\n--- second\n%s",
+			string(data), string(again.Data()))
+	}
+}
+
+func TestPoDataLineEnding(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "test.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	poFile.LineEnding = "\r\n"
+	for _, line := range strings.SplitAfter(string(poFile.Data()), "\n") {
+		if line != "" && !strings.HasSuffix(line, "\r\n") {
+			t.Fatalf("line without CRLF ending: %q", line)
+		}
+	}
+}
+
+func TestParsePoDataMsgCtxt(t *testing.T) {
+	data := `msgid ""
+msgstr ""
+"Project-Id-Version: git-po-helper\n"
+
+msgctxt "column"
+msgid "Status"
+msgstr "状态"
+
+msgid "Status"
+msgstr "状态报告"
+`
+	poFile, err := ParsePoData([]byte(data), "test.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data with msgctxt: %s", err)
+	}
+	if len(poFile.Entries) != 2 {
+		t.Fatalf("parsed %d entries, expect 2", len(poFile.Entries))
+	}
+	if poFile.Entries[0].MsgCtxt != "column" {
+		t.Errorf("bad msgctxt: %q", poFile.Entries[0].MsgCtxt)
+	}
+	if poFile.Entries[0].Key() == poFile.Entries[1].Key() {
+		t.Error("contexted entry conflated with plain entry of the same msgid")
+	}
+	again, err := ParsePoData(poFile.Data(), "test.po")
+	if err != nil {
+		t.Fatalf("fail to reparse serialized po data: %s", err)
+	}
+	if string(again.Data()) != string(poFile.Data()) {
+		t.Errorf("msgctxt not stable in round trip:\n%s", string(again.Data()))
+	}
+}
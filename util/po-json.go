@@ -0,0 +1,183 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GettextJSON is a lossless JSON representation of one po entry, for
+// scripts and agents which would rather not parse po syntax.
+type GettextJSON struct {
+	Index        int      `json:"index"`
+	Comments     []string `json:"comments,omitempty"`
+	MsgCtxt      string   `json:"msgctxt,omitempty"`
+	MsgID        string   `json:"msgid"`
+	MsgIDPlural  string   `json:"msgid_plural,omitempty"`
+	MsgStr       string   `json:"msgstr,omitempty"`
+	MsgStrPlural []string `json:"msgstr_plural,omitempty"`
+	Fuzzy        bool     `json:"fuzzy,omitempty"`
+	Obsolete     bool     `json:"obsolete,omitempty"`
+}
+
+// BuildGettextJSON converts the entries of a po file to their JSON
+// representation. The header entry has index 0.
+func BuildGettextJSON(poFile *PoFile) []GettextJSON {
+	var entries []GettextJSON
+
+	for i, entry := range poFile.Entries {
+		entries = append(entries, GettextJSON{
+			Index:        i,
+			Comments:     entry.Comments,
+			MsgCtxt:      entry.MsgCtxt,
+			MsgID:        entry.MsgID,
+			MsgIDPlural:  entry.MsgIDPlural,
+			MsgStr:       entry.MsgStr,
+			MsgStrPlural: entry.MsgStrPlural,
+			Fuzzy:        entry.IsFuzzy(),
+			Obsolete:     entry.Obsolete,
+		})
+	}
+	return entries
+}
+
+// WriteGettextJSONToPO merges JSON entries back into a po file.
+// Entries are matched by msgctxt and msgid, and only translations are
+// updated, comments and entry order of the po file are kept.
+func WriteGettextJSONToPO(poFile *PoFile, entries []GettextJSON) error {
+	byKey := make(map[string]*PoEntry)
+	for _, entry := range poFile.Entries {
+		byKey[entry.Key()] = entry
+	}
+	for _, entry := range entries {
+		target, ok := byKey[poEntryKey(entry.MsgCtxt, entry.MsgID)]
+		if !ok {
+			return fmt.Errorf(`no entry with msgid "%s" to import`, entry.MsgID)
+		}
+		target.MsgStr = entry.MsgStr
+		if len(entry.MsgStrPlural) > 0 {
+			target.MsgStrPlural = entry.MsgStrPlural
+		}
+	}
+	return nil
+}
+
+// parseIndexRange parses an entry range such as "200", "101-200" or
+// "101-" into begin and end indices. End of 0 means no upper bound.
+func parseIndexRange(spec string) (int, int, error) {
+	var (
+		begin, end int
+		err        error
+	)
+
+	fail := func() (int, int, error) {
+		return 0, 0, fmt.Errorf(`invalid range "%s", expect "<begin>-<end>"`, spec)
+	}
+	if !strings.Contains(spec, "-") {
+		if begin, err = strconv.Atoi(spec); err != nil {
+			return fail()
+		}
+		return begin, begin, nil
+	}
+	fields := strings.SplitN(spec, "-", 2)
+	if fields[0] != "" {
+		if begin, err = strconv.Atoi(fields[0]); err != nil {
+			return fail()
+		}
+	}
+	if fields[1] != "" {
+		if end, err = strconv.Atoi(fields[1]); err != nil {
+			return fail()
+		}
+	}
+	if end != 0 && end < begin {
+		return fail()
+	}
+	return begin, end, nil
+}
+
+// CmdPoJSONExport implements po-json export sub command. It writes the
+// JSON representation of a po file, optionally restricted to a range
+// of entry indices.
+func CmdPoJSONExport(fileName string) bool {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	entries := BuildGettextJSON(poFile)
+	if spec := FlagPoJSONRange(); spec != "" {
+		begin, end, err := parseIndexRange(spec)
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		var selected []GettextJSON
+		for _, entry := range entries {
+			if entry.Index < begin || (end != 0 && entry.Index > end) {
+				continue
+			}
+			selected = append(selected, entry)
+		}
+		entries = selected
+	}
+	buf, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	output := FlagPoJSONOutput()
+	if output == "" {
+		output = "-"
+	}
+	if err = WriteOutput(output, append(buf, '\n')); err != nil {
+		log.Error(err)
+		return false
+	}
+	return true
+}
+
+// CmdPoJSONImport implements po-json import sub command. It merges the
+// translations of a JSON file back into the po file given by option
+// "-o", and validates the result.
+func CmdPoJSONImport(fileName string) bool {
+	output := FlagPoJSONOutput()
+	if output == "" {
+		log.Errorf(`please provide the target po file with option "-o"`)
+		return false
+	}
+	buf, err := ReadInput(fileName)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	var entries []GettextJSON
+	if err = json.Unmarshal(buf, &entries); err != nil {
+		log.Errorf(`fail to parse "%s": %s`, fileName, err)
+		return false
+	}
+	poFile, err := LoadPoFile(output)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if err = WriteGettextJSONToPO(poFile, entries); err != nil {
+		log.Error(err)
+		return false
+	}
+	if err = poFile.Save(output); err != nil {
+		log.Error(err)
+		return false
+	}
+	if errs, ok := checkPoSyntax(output); !ok {
+		for _, err := range errs {
+			log.Error(err)
+		}
+		return false
+	}
+	log.Infof(`imported %d entries into "%s"`, len(entries), output)
+	return true
+}
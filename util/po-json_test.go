@@ -0,0 +1,66 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestParseIndexRange(t *testing.T) {
+	for _, tc := range []struct {
+		spec       string
+		begin, end int
+	}{
+		{"200", 200, 200},
+		{"101-200", 101, 200},
+		{"101-", 101, 0},
+		{"-200", 0, 200},
+		{"0-0", 0, 0},
+	} {
+		begin, end, err := parseIndexRange(tc.spec)
+		if err != nil {
+			t.Errorf("fail to parse range %q: %s", tc.spec, err)
+			continue
+		}
+		if begin != tc.begin || end != tc.end {
+			t.Errorf("range %q gives (%d, %d), expect (%d, %d)",
+				tc.spec, begin, end, tc.begin, tc.end)
+		}
+	}
+	for _, spec := range []string{"", "abc", "1-x", "x-1", "200-101"} {
+		if _, _, err := parseIndexRange(spec); err == nil {
+			t.Errorf("no error for invalid range %q", spec)
+		}
+	}
+}
+
+func TestGettextJSONRoundTrip(t *testing.T) {
+	poFile, err := ParsePoData([]byte(poSample), "zh_CN.po")
+	if err != nil {
+		t.Fatalf("fail to parse po data: %s", err)
+	}
+	entries := BuildGettextJSON(poFile)
+	if len(entries) != len(poFile.Entries) {
+		t.Fatalf("exported %d entries, expect %d", len(entries), len(poFile.Entries))
+	}
+	if entries[0].Index != 0 || entries[0].MsgID != "could not open '%s'" {
+		t.Errorf("bad first entry: %+v", entries[0])
+	}
+	if entries[1].MsgIDPlural != "%d files" || len(entries[1].MsgStrPlural) == 0 {
+		t.Errorf("plural entry not exported: %+v", entries[1])
+	}
+	if !entries[2].Fuzzy {
+		t.Errorf("fuzzy flag not exported: %+v", entries[2])
+	}
+
+	entries[3].MsgStr = "未翻译条目"
+	if err = WriteGettextJSONToPO(poFile, entries[3:4]); err != nil {
+		t.Fatalf("fail to import entries: %s", err)
+	}
+	if poFile.Entries[3].MsgStr != "未翻译条目" {
+		t.Errorf("imported msgstr not applied: %q", poFile.Entries[3].MsgStr)
+	}
+
+	entries[3].MsgID = "no such entry"
+	if err = WriteGettextJSONToPO(poFile, entries[3:4]); err == nil {
+		t.Error("no error importing an entry with unknown msgid")
+	}
+}
@@ -0,0 +1,77 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// lockMarker marks curated entries which agents must never modify.
+const lockMarker = "# locked"
+
+// IsLocked indicates the entry is protected from modification by
+// agents.
+func (v *PoEntry) IsLocked() bool {
+	for _, comment := range v.Comments {
+		if comment == lockMarker || strings.HasPrefix(comment, lockMarker+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// lockedEntries returns the translations of locked entries of a po
+// file, keyed by msgid.
+func lockedEntries(fileName string) map[string]string {
+	locked := make(map[string]string)
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return locked
+	}
+	for _, entry := range poFile.Entries {
+		if entry.IsLocked() {
+			locked[entry.Key()] = entry.MsgStr
+		}
+	}
+	return locked
+}
+
+// lockedEntriesPromptNote returns a prompt sentence which tells the
+// agent not to touch locked entries, or an empty string if the po file
+// has none.
+func lockedEntriesPromptNote(fileName string) string {
+	nr := len(lockedEntries(fileName))
+	if nr == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`%d entries are marked with a "%s" comment, `+
+		`they are curated translations and must not be modified.`,
+		nr, lockMarker)
+}
+
+// enforceLockedEntries restores locked entries which were modified by
+// an agent, and reports each violation.
+func enforceLockedEntries(fileName string, before map[string]string) error {
+	if len(before) == 0 {
+		return nil
+	}
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return err
+	}
+	restored := 0
+	for _, entry := range poFile.Entries {
+		msgStr, ok := before[entry.Key()]
+		if !ok || entry.MsgStr == msgStr {
+			continue
+		}
+		log.Warnf(`agent modified locked entry "%s", restoring`, entry.MsgID)
+		entry.MsgStr = msgStr
+		restored++
+	}
+	if restored == 0 {
+		return nil
+	}
+	return poFile.Save(fileName)
+}
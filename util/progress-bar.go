@@ -0,0 +1,104 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// progressBarWidth is the width of the bar drawn on the terminal.
+const progressBarWidth = 30
+
+// progressLogInterval limits how often progress is logged when stdout
+// is not a terminal.
+const progressLogInterval = 10 * time.Second
+
+// progressBar shows the progress of a long-running batched operation.
+// It renders a live progress bar with an ETA when stdout is a
+// terminal, and falls back to periodic log lines otherwise.
+type progressBar struct {
+	stage   string
+	total   int
+	done    int
+	start   time.Time
+	lastLog time.Time
+	isTTY   bool
+	mu      sync.Mutex
+}
+
+// newProgressBar creates a progress bar for an operation consisting of
+// total steps.
+func newProgressBar(stage string, total int) *progressBar {
+	isTTY := false
+	if fi, err := os.Stdout.Stat(); err == nil {
+		isTTY = fi.Mode()&os.ModeCharDevice != 0
+	}
+	return &progressBar{
+		stage: stage,
+		total: total,
+		start: time.Now(),
+		isTTY: isTTY,
+	}
+}
+
+// Add marks n more steps of the operation as done, and redraws the
+// progress bar.
+func (v *progressBar) Add(n int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.done += n
+	if v.done > v.total {
+		v.done = v.total
+	}
+	elapsed := time.Since(v.start).Round(time.Second)
+	if tuiActive() {
+		setTUIStatus(v.stage, fmt.Sprintf("%d/%d, elapsed %s, ETA %s",
+			v.done, v.total, elapsed, v.eta()))
+		return
+	}
+	if !v.isTTY {
+		if time.Since(v.lastLog) < progressLogInterval && v.done < v.total {
+			return
+		}
+		v.lastLog = time.Now()
+		log.Infof("%s: %d/%d done, elapsed %s, ETA %s",
+			v.stage, v.done, v.total, elapsed, v.eta())
+		return
+	}
+	width := v.done * progressBarWidth / v.total
+	fmt.Printf("\r%s: [%s%s] %3d%% (%d/%d) elapsed %s, ETA %s ",
+		v.stage,
+		strings.Repeat("#", width),
+		strings.Repeat(" ", progressBarWidth-width),
+		v.done*100/v.total,
+		v.done, v.total,
+		elapsed, v.eta())
+}
+
+// Done finishes the progress bar, and moves the cursor to the next
+// line on the terminal.
+func (v *progressBar) Done() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.isTTY && v.done > 0 {
+		fmt.Println()
+	}
+}
+
+// eta estimates the remaining duration of the operation from the pace
+// of the finished steps.
+func (v *progressBar) eta() string {
+	if v.done == 0 {
+		return "unknown"
+	}
+	if v.done >= v.total {
+		return "0s"
+	}
+	elapsed := time.Since(v.start)
+	remain := time.Duration(int64(elapsed) / int64(v.done) * int64(v.total-v.done))
+	return remain.Round(time.Second).String()
+}
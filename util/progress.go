@@ -0,0 +1,51 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// ProgressEvent is a machine-parseable progress event, written as one
+// line of JSON to the file descriptor given by option "--progress-fd".
+type ProgressEvent struct {
+	// Stage of the running operation, such as "translate".
+	Stage string `json:"stage"`
+
+	// Percent of completeness of the running operation.
+	Percent float64 `json:"percent"`
+
+	// Batch is the current batch number, starting from 1.
+	Batch int `json:"batch,omitempty"`
+
+	// NrBatches is the total number of batches.
+	NrBatches int `json:"nr_batches,omitempty"`
+
+	// Message is an optional human readable description.
+	Message string `json:"message,omitempty"`
+}
+
+var (
+	progressFile *os.File
+	progressOnce sync.Once
+)
+
+// EmitProgress writes a progress event to the progress file descriptor,
+// and is a no-op if option "--progress-fd" is not given.
+func EmitProgress(event ProgressEvent) {
+	progressOnce.Do(func() {
+		fd := FlagProgressFd()
+		if fd <= 0 {
+			return
+		}
+		progressFile = os.NewFile(uintptr(fd), "progress")
+	})
+	if progressFile == nil {
+		return
+	}
+	buf, err := json.Marshal(&event)
+	if err != nil {
+		return
+	}
+	progressFile.Write(append(buf, '\n'))
+}
@@ -0,0 +1,107 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"text/template"
+)
+
+// Limits for the context injected into template prompts, so a rendered
+// prompt stays within a sane size.
+const (
+	promptGlossaryLimit     = 20
+	promptUntranslatedLimit = 50
+)
+
+// PromptContext is the structured context available to prompts written
+// as Go templates, computed by the helper before the agent call.
+type PromptContext struct {
+	// Lang is the locale code, such as "zh_CN".
+	Lang string
+
+	// LangName is the pretty name of the language, such as
+	// "Chinese - China (Mainland)".
+	LangName string
+
+	// Source is the po file the agent works on.
+	Source string
+
+	// NrEntries is the number of translatable entries of the po file.
+	NrEntries int
+
+	// PluralForms is the "Plural-Forms" header of the po file.
+	PluralForms string
+
+	// Glossary is an excerpt of the glossary of the locale.
+	Glossary []GlossaryEntry
+
+	// Untranslated lists msgids which are untranslated or fuzzy.
+	Untranslated []string
+
+	// CommitRange covers the commits since the po file was last
+	// touched, such as "1234abcd..HEAD".
+	CommitRange string
+}
+
+// newPromptContext computes the template context for running an agent
+// on a po file of the given locale.
+func newPromptContext(locale, fileName string) *PromptContext {
+	ctx := &PromptContext{
+		Lang:   locale,
+		Source: fileName,
+	}
+	if name, err := GetPrettyLocaleName(locale); err == nil {
+		ctx.LangName = name
+	}
+	ctx.NrEntries = nrEntries(fileName)
+	ctx.PluralForms = poHeaderField(fileName, "Plural-Forms")
+	if glossary, err := LoadGlossary(locale); err == nil {
+		if len(glossary) > promptGlossaryLimit {
+			glossary = glossary[:promptGlossaryLimit]
+		}
+		ctx.Glossary = glossary
+	}
+	untranslated := remainingMsgIDs(fileName)
+	if len(untranslated) > promptUntranslatedLimit {
+		untranslated = untranslated[:promptUntranslatedLimit]
+	}
+	ctx.Untranslated = untranslated
+	cmd := exec.Command("git", "log", "-1", "--format=%h", "--", fileName)
+	cmd.Dir = GitRootDir
+	if out, err := cmd.Output(); err == nil {
+		if commit := strings.TrimSpace(string(out)); commit != "" {
+			ctx.CommitRange = commit + "..HEAD"
+		}
+	}
+	return ctx
+}
+
+// poHeaderField returns a field of the po file header, such as
+// "Plural-Forms".
+func poHeaderField(fileName, name string) string {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return ""
+	}
+	return poFile.HeaderField(name)
+}
+
+// expandPromptTemplate renders a prompt written as a Go template with
+// the given context. Prompts without template actions are returned
+// unchanged, so plain "{placeholder}" prompts keep working.
+func expandPromptTemplate(prompt string, ctx *PromptContext) (string, error) {
+	if !strings.Contains(prompt, "{{") {
+		return prompt, nil
+	}
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(prompt)
+	if err != nil {
+		return "", fmt.Errorf("fail to parse prompt template: %s", err)
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("fail to render prompt template: %s", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// configPromptNames are the prompts which can be customized in the
+// "prompt" section of the config file.
+var configPromptNames = []string{"translate", "review"}
+
+// configPrompt returns the prompt of config variable "prompt.<name>".
+// A value starting with "@" references an external file, which is read
+// again on every run, so prompt edits take effect without touching the
+// YAML config. Returns an empty string if the prompt is not
+// customized.
+func configPrompt(name string) (string, error) {
+	value := viper.GetString("prompt." + name)
+	if value == "" {
+		return "", nil
+	}
+	if strings.HasPrefix(value, "@") {
+		buf, err := ioutil.ReadFile(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return "", fmt.Errorf(`fail to load prompt "%s": %s`, name, err)
+		}
+		value = string(buf)
+	}
+	for _, placeholder := range placeholderPattern.FindAllString(value, -1) {
+		if !knownPlaceholders[placeholder] {
+			return "", fmt.Errorf(
+				`unknown placeholder "%s" in prompt "%s"`, placeholder, name)
+		}
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// translatePrompt returns the effective translate prompt override: the
+// "--prompt" option of the run wins over config variable
+// "prompt.translate".
+func translatePrompt(agent *Agent) (string, error) {
+	if agent.Prompt != "" {
+		return agent.Prompt, nil
+	}
+	return configPrompt("translate")
+}
@@ -0,0 +1,199 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ReviewRecord is one line of the review history file, the outcome of
+// reviewing one po file in one agent-run review invocation.
+type ReviewRecord struct {
+	Time   string        `json:"time"`
+	Locale string        `json:"locale"`
+	Score  int           `json:"score"`
+	Commit string        `json:"commit,omitempty"`
+	Issues []ReviewIssue `json:"issues"`
+}
+
+// reviewHistoryFile is the JSON-lines file which persists past review
+// results, so score trends and recurring issues are visible over time.
+func reviewHistoryFile() string {
+	return filepath.Join(HelperDir, "history", "reviews.jsonl")
+}
+
+// recordReviewResult appends the outcome of one review to the history
+// file.
+func recordReviewResult(locale string, score int, result *ReviewJSONResult) {
+	record := ReviewRecord{
+		Time:   time.Now().Format(time.RFC3339),
+		Locale: locale,
+		Score:  score,
+		Commit: poCommitID(),
+		Issues: result.Issues,
+	}
+	fileName := reviewHistoryFile()
+	var err error
+	if err = os.MkdirAll(filepath.Dir(fileName), 0755); err == nil {
+		var f *os.File
+		if f, err = os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			defer f.Close()
+			var buf []byte
+			if buf, err = json.Marshal(&record); err == nil {
+				_, err = f.Write(append(buf, '\n'))
+			}
+		}
+	}
+	if err != nil {
+		log.Errorf("fail to save review history: %s", err)
+	}
+}
+
+// loadReviewRecords reads all review records from the history file,
+// and returns an empty list if there is none.
+func loadReviewRecords() ([]ReviewRecord, error) {
+	var records []ReviewRecord
+
+	f, err := os.Open(reviewHistoryFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		record := ReviewRecord{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("invalid review record at %s:%d: %s",
+				reviewHistoryFile(), lineNo, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// CmdReportReview implements report review sub command. It reads the
+// review history, and prints score trends, recurring msgids with
+// issues, and the issue category distribution per language, to help
+// coordinators prioritize glossary or style-guide fixes.
+func CmdReportReview(args ...string) bool {
+	records, err := loadReviewRecords()
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if len(records) == 0 {
+		log.Infof(`no review records in "%s"`, reviewHistoryFile())
+		return true
+	}
+	var locales []string
+	byLocale := make(map[string][]ReviewRecord)
+	for _, record := range records {
+		if len(args) > 0 && !matchLocaleArgs(record.Locale, args) {
+			continue
+		}
+		if _, ok := byLocale[record.Locale]; !ok {
+			locales = append(locales, record.Locale)
+		}
+		byLocale[record.Locale] = append(byLocale[record.Locale], record)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		printReviewTrend(locale, byLocale[locale])
+	}
+	return true
+}
+
+// matchLocaleArgs reports whether a locale is selected by the "XX.po"
+// or "XX" arguments of report review.
+func matchLocaleArgs(locale string, args []string) bool {
+	for _, arg := range args {
+		name, _ := resolvePoFile(arg)
+		if name == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// printReviewTrend prints the score trend, recurring issues and issue
+// category distribution of one language.
+func printReviewTrend(locale string, records []ReviewRecord) {
+	first := records[0]
+	last := records[len(records)-1]
+	fmt.Printf("Language: %s\n", locale)
+	fmt.Printf("  Reviews:     %d (%s .. %s)\n",
+		len(records), first.Time, last.Time)
+	fmt.Printf("  Score trend: %d -> %d (%+d)\n",
+		first.Score, last.Score, last.Score-first.Score)
+
+	seen := make(map[string]int)
+	var msgids []string
+	categories := make(map[string]int)
+	var names []string
+	for _, record := range records {
+		inRecord := make(map[string]bool)
+		for _, issue := range record.Issues {
+			// Count each msgid once per review, so one noisy
+			// review does not look like a recurring problem.
+			if !inRecord[issue.MsgID] {
+				inRecord[issue.MsgID] = true
+				if seen[issue.MsgID] == 0 {
+					msgids = append(msgids, issue.MsgID)
+				}
+				seen[issue.MsgID]++
+			}
+			category := reviewIssueCategory(issue)
+			if categories[category] == 0 {
+				names = append(names, category)
+			}
+			categories[category]++
+		}
+	}
+	var recurring []string
+	for _, msgid := range msgids {
+		if seen[msgid] > 1 {
+			recurring = append(recurring, msgid)
+		}
+	}
+	sort.SliceStable(recurring, func(i, j int) bool {
+		return seen[recurring[i]] > seen[recurring[j]]
+	})
+	if len(recurring) > 0 {
+		fmt.Printf("  Recurring issues:\n")
+		for i, msgid := range recurring {
+			if i >= 10 {
+				fmt.Printf("    ... %d more\n", len(recurring)-i)
+				break
+			}
+			fmt.Printf("    %dx %s\n", seen[msgid], truncateAgentLine(msgid))
+		}
+	}
+	if len(names) > 0 {
+		sort.SliceStable(names, func(i, j int) bool {
+			return categories[names[i]] > categories[names[j]]
+		})
+		var parts []string
+		for _, name := range names {
+			parts = append(parts, fmt.Sprintf("%s %d", name, categories[name]))
+		}
+		fmt.Printf("  Categories:  %s\n", strings.Join(parts, ", "))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,116 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// reportRange returns the commit range a PR description covers: the
+// given argument, the upstream range "@{u}..HEAD", or the last commit
+// as a fallback.
+func reportRange(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	cmd := exec.Command("git", "rev-parse", "--verify", "-q", "@{u}")
+	cmd.Dir = GitRootDir
+	if err := cmd.Run(); err == nil {
+		return "@{u}..HEAD"
+	}
+	return "HEAD^..HEAD"
+}
+
+// changedPoLocales returns the locales whose po files changed in the
+// given commit range.
+func changedPoLocales(commitRange string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", commitRange, "--", PoDir)
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf(`fail to diff range "%s": %s`, commitRange, err)
+	}
+	var locales []string
+	for _, path := range strings.Fields(string(buf)) {
+		if filepath.Dir(path) != PoDir || filepath.Ext(path) != ".po" {
+			continue
+		}
+		locale := strings.TrimSuffix(filepath.Base(path), ".po")
+		if _, err := GetPrettyLocaleName(locale); err == nil {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+	return locales, nil
+}
+
+// translatedInRange returns how many entries of a po file became
+// translated in the commit range, or 0 if history is not available.
+func translatedInRange(locale, commitRange string) int {
+	poFile := filepath.Join(PoDir, locale+".po")
+	base := strings.SplitN(commitRange, "..", 2)[0]
+	oldPo, err := loadPoFileOrBlob(base + ":" + poFile)
+	if err != nil {
+		return 0
+	}
+	nrOld := 0
+	for _, entry := range oldPo.Entries {
+		if !entry.Obsolete && entry.MsgID != "" &&
+			entry.IsTranslated() && !entry.IsFuzzy() {
+			nrOld++
+		}
+	}
+	count := nrTranslated(poFile) - nrOld
+	if count < 0 {
+		count = 0
+	}
+	return count
+}
+
+// CmdReportPrBody implements report pr-body sub command. It writes a
+// Markdown description of the l10n changes in a commit range, ready to
+// paste into the git-l10n pull request.
+func CmdReportPrBody(args ...string) bool {
+	commitRange := reportRange(args)
+	locales, err := changedPoLocales(commitRange)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	if len(locales) == 0 {
+		log.Errorf(`no po files changed in range "%s"`, commitRange)
+		return false
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## l10n updates (%s)\n\n", commitRange)
+	fmt.Fprintf(&sb, "Languages: %s\n\n", strings.Join(locales, ", "))
+	sb.WriteString("| Language | Completeness | New translations | Fuzzy | Untranslated |\n")
+	sb.WriteString("|----------|--------------|------------------|-------|--------------|\n")
+	for _, locale := range locales {
+		stats, err := StatPoFile(filepath.Join(PoDir, locale+".po"))
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		fmt.Fprintf(&sb, "| %s | %.1f%% (%d/%d) | %d | %d | %d |\n",
+			stats.displayName(), stats.Percent(), stats.Translated, stats.Total,
+			translatedInRange(locale, commitRange), stats.Fuzzy, stats.Untranslated)
+	}
+
+	// Mention recent benchmark scores, if any review or translate runs
+	// were recorded.
+	if records, err := loadBenchmarkRecords(); err == nil && len(records) > 0 {
+		record := records[len(records)-1]
+		fmt.Fprintf(&sb, "\nLatest benchmark: agent %q scored %d over %d runs (%s).\n",
+			record.Agent, record.AvgScore, record.Runs, record.Action)
+	}
+
+	fmt.Fprint(os.Stdout, sb.String())
+	return true
+}
@@ -7,31 +7,93 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
+	"strings"
 )
 
-// GitRootDir is the root dir of current worktree.
+// GitRootDir is the root dir of current worktree. It is the implicit
+// global repository the commands work on; library users who need
+// several repositories at once should use explicit Repository handles
+// instead.
 var GitRootDir string
 
-// OpenRepository will try to find root dir for current workspace.
-func OpenRepository(workDir string) error {
-	var (
-		dir string
-		err error
-	)
+// Repository is an explicit handle of one git worktree. Functions
+// which take a Repository can run concurrently on different worktrees,
+// unlike the package level shims which share GitRootDir.
+type Repository struct {
+	// RootDir is the root dir of the worktree.
+	RootDir string
+}
 
+// NewRepository opens an explicit repository handle for the given work
+// dir, without touching the global GitRootDir.
+func NewRepository(workDir string) (*Repository, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	cmd.Dir = workDir
 	out, err := cmd.Output()
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
-			return errors.New(string(exitError.Stderr))
+			return nil, errors.New(string(exitError.Stderr))
 		}
-		return err
+		return nil, err
 	}
-	dir = string(bytes.TrimSpace(out))
+	dir := string(bytes.TrimSpace(out))
 	if _, err := os.Stat(path.Join(dir, PoDir, GitPot)); err != nil {
-		return fmt.Errorf(`cannot find "%s/%s", this command is for git project`, PoDir, GitPot)
+		return nil, fmt.Errorf(`cannot find "%s/%s", this command is for git project`, PoDir, GitPot)
 	}
-	GitRootDir = dir
+	return &Repository{RootDir: dir}, nil
+}
+
+// DefaultRepository returns the handle of the repository opened by
+// OpenRepository, which the package level shims work on.
+func DefaultRepository() *Repository {
+	return &Repository{RootDir: GitRootDir}
+}
+
+// WorkDir returns the root dir of the worktree.
+func (v *Repository) WorkDir() string {
+	return v.RootDir
+}
+
+// PoFile returns the path of "po/XX.po" inside the repository.
+func (v *Repository) PoFile(locale string) string {
+	return filepath.Join(v.RootDir, PoDir, locale+".po")
+}
+
+// PotFile returns the path of "po/git.pot" inside the repository.
+func (v *Repository) PotFile() string {
+	return filepath.Join(v.RootDir, PoDir, GitPot)
+}
+
+// GitCommand creates a git command running inside the repository.
+func (v *Repository) GitCommand(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = v.RootDir
+	return cmd
+}
+
+// OpenRepository finds the root dir of the current workspace, and sets
+// it as the implicit global repository.
+func OpenRepository(workDir string) error {
+	repo, err := NewRepository(workDir)
+	if err != nil {
+		return err
+	}
+	GitRootDir = repo.RootDir
 	return nil
 }
+
+// resolvePoFile maps a command line argument to the po file to work
+// on. Inside a repository, arguments name locales resolved to
+// "po/XX.po"; with option "--no-repo", the argument is used as the
+// file path itself.
+func resolvePoFile(fileName string) (string, string) {
+	locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+	if FlagNoRepo() {
+		if filepath.Ext(fileName) != ".po" {
+			fileName += ".po"
+		}
+		return locale, fileName
+	}
+	return locale, filepath.Join(PoDir, locale+".po")
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reviewCommentPrefix marks translator comments written from review
+// issues, so stale comments of an earlier review can be removed.
+const reviewCommentPrefix = "#. review:"
+
+// annotateReviewResult writes a copy of a po file with each review
+// issue as a "#. review:" translator comment above the corresponding
+// entry, so translators see the agent feedback in their normal po
+// editor instead of a separate JSON document. Review comments of an
+// earlier run are dropped first. Returns the name of the annotated
+// copy, such as "po/XX-review.po".
+func annotateReviewResult(fileName string, result *ReviewJSONResult) (string, error) {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return "", err
+	}
+	issues := make(map[string][]ReviewIssue)
+	for _, issue := range result.Issues {
+		key := poEntryKey(issue.MsgCtxt, issue.MsgID)
+		issues[key] = append(issues[key], issue)
+	}
+	for _, entry := range poFile.Entries {
+		entry.Comments = dropReviewComments(entry.Comments)
+		var comments []string
+		for _, issue := range issues[entry.Key()] {
+			comments = append(comments, reviewComments(issue)...)
+		}
+		if len(comments) > 0 {
+			entry.Comments = insertReviewComments(entry.Comments, comments)
+		}
+	}
+	outFile := strings.TrimSuffix(fileName, ".po") + "-review.po"
+	if err = poFile.Save(outFile); err != nil {
+		return "", err
+	}
+	return outFile, nil
+}
+
+// reviewComments formats one review issue as translator comment lines.
+func reviewComments(issue ReviewIssue) []string {
+	severity := issue.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+	comments := []string{fmt.Sprintf("%s (%s) %s",
+		reviewCommentPrefix, severity, flattenComment(issue.Explanation))}
+	if issue.Suggestion != "" {
+		comments = append(comments, fmt.Sprintf("%s suggestion: %s",
+			reviewCommentPrefix, flattenComment(issue.Suggestion)))
+	}
+	return comments
+}
+
+// dropReviewComments removes the "#. review:" comments of an earlier
+// review, so annotations do not pile up over repeated reviews.
+func dropReviewComments(comments []string) []string {
+	var kept []string
+	for _, comment := range comments {
+		if strings.HasPrefix(comment, reviewCommentPrefix) {
+			continue
+		}
+		kept = append(kept, comment)
+	}
+	return kept
+}
+
+// insertReviewComments inserts translator comments before the "#:"
+// reference and "#," flag comments of an entry, following the comment
+// order convention of gettext tools.
+func insertReviewComments(comments, review []string) []string {
+	idx := len(comments)
+	for i, comment := range comments {
+		if strings.HasPrefix(comment, "#:") || strings.HasPrefix(comment, "#,") {
+			idx = i
+			break
+		}
+	}
+	var merged []string
+	merged = append(merged, comments[:idx]...)
+	merged = append(merged, review...)
+	merged = append(merged, comments[idx:]...)
+	return merged
+}
+
+// flattenComment folds a multi-line text into one comment line.
+func flattenComment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// reviewCache holds review results of single entries, keyed by a hash
+// of msgid, msgstr, and the review prompt, so unchanged entries are
+// not sent to the agent again.
+type reviewCache struct {
+	Entries map[string][]ReviewIssue `json:"entries"`
+}
+
+func reviewCacheFile() string {
+	return filepath.Join(HelperDir, "cache", "review-cache.json")
+}
+
+// loadReviewCache reads the review cache, and returns an empty cache
+// if there is none.
+func loadReviewCache() *reviewCache {
+	cache := &reviewCache{Entries: make(map[string][]ReviewIssue)}
+	buf, err := ioutil.ReadFile(reviewCacheFile())
+	if err != nil {
+		return cache
+	}
+	if err = json.Unmarshal(buf, cache); err != nil || cache.Entries == nil {
+		cache.Entries = make(map[string][]ReviewIssue)
+	}
+	return cache
+}
+
+// saveReviewCache writes the review cache.
+func saveReviewCache(cache *reviewCache) error {
+	if err := os.MkdirAll(filepath.Dir(reviewCacheFile()), 0755); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(reviewCacheFile(), append(buf, '\n'), 0644)
+}
+
+// reviewCacheKey returns the cache key of one entry. The review prompt
+// is part of the key, so prompt changes invalidate the cache.
+func reviewCacheKey(msgID, msgStr, promptHash string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s", msgID, msgStr, promptHash)
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// reviewWithCache runs the agent to review a po file, skipping entries
+// with a cached review result, and merges cached issues back into the
+// report.
+func reviewWithCache(ctx context.Context, agent *Agent, poFile, locale string) (string, error) {
+	prompt := agent.Prompt
+	if prompt == "" {
+		prompt = reviewPromptForLocale(locale)
+	}
+	promptHash := fmt.Sprintf("%x", sha256.Sum256([]byte(prompt)))[:12]
+
+	newFile, err := LoadPoFile(poFile)
+	if err != nil {
+		return "", err
+	}
+	var (
+		cache    = loadReviewCache()
+		cached   []ReviewIssue
+		uncached []*PoEntry
+		subset   = PoFile{
+			Header:     newFile.Header,
+			LineEnding: newFile.LineEnding,
+		}
+	)
+	for _, entry := range newFile.Entries {
+		if entry.Obsolete {
+			continue
+		}
+		key := reviewCacheKey(entry.Key(), entry.MsgStr, promptHash)
+		if issues, ok := cache.Entries[key]; ok {
+			cached = append(cached, issues...)
+			continue
+		}
+		uncached = append(uncached, entry)
+		subset.Entries = append(subset.Entries, entry)
+	}
+	log.Infof(`reviewing "%s": %d entries cached, %d to review`,
+		poFile, len(newFile.Entries)-len(uncached), len(uncached))
+
+	result := &ReviewJSONResult{Issues: []ReviewIssue{}}
+	if len(uncached) > 0 {
+		tmpFile, err := ioutil.TempFile("", "pofile")
+		if err != nil {
+			return "", err
+		}
+		defer os.Remove(tmpFile.Name())
+		tmpFile.Close()
+		if err = subset.Save(tmpFile.Name()); err != nil {
+			return "", err
+		}
+		report, err := runAgentReviewFile(ctx, agent, tmpFile.Name(), locale)
+		if err != nil {
+			return "", err
+		}
+		if FlagAgentDryRun() {
+			return report, nil
+		}
+		if result, err = ParseReviewJSON([]byte(report)); err != nil {
+			return report, nil
+		}
+		issuesByMsgID := make(map[string][]ReviewIssue)
+		for _, issue := range result.Issues {
+			key := poEntryKey(issue.MsgCtxt, issue.MsgID)
+			issuesByMsgID[key] = append(issuesByMsgID[key], issue)
+		}
+		for _, entry := range uncached {
+			key := reviewCacheKey(entry.Key(), entry.MsgStr, promptHash)
+			issues := issuesByMsgID[entry.Key()]
+			if issues == nil {
+				issues = []ReviewIssue{}
+			}
+			cache.Entries[key] = issues
+		}
+		if err = saveReviewCache(cache); err != nil {
+			log.Warnf("fail to save review cache: %s", err)
+		}
+	}
+	result.Issues = append(result.Issues, cached...)
+	buf, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(buf) + "\n", nil
+}
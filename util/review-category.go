@@ -0,0 +1,88 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// defaultReviewCategories is the default issue category taxonomy of
+// review results.
+var defaultReviewCategories = []string{
+	"terminology",
+	"grammar",
+	"placeholder",
+	"punctuation",
+	"omission",
+}
+
+// reviewCategories returns the issue category taxonomy, set by config
+// variable "review.categories" as a list of category names.
+func reviewCategories() []string {
+	if categories := viper.GetStringSlice("review.categories"); len(categories) > 0 {
+		return categories
+	}
+	return defaultReviewCategories
+}
+
+// isReviewCategory reports whether a category name is part of the
+// taxonomy.
+func isReviewCategory(name string) bool {
+	for _, category := range reviewCategories() {
+		if category == name {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewIssueCategory returns the category of one review issue,
+// preferring the category reported by the agent, and falling back to
+// keywords of the explanation for uncategorized issues.
+func reviewIssueCategory(issue ReviewIssue) string {
+	if issue.Category != "" {
+		return issue.Category
+	}
+	explanation := strings.ToLower(issue.Explanation)
+	switch {
+	case strings.Contains(explanation, "placeholder") ||
+		strings.Contains(explanation, "format specifier"):
+		return "placeholder"
+	case strings.Contains(explanation, "accelerator") ||
+		strings.Contains(explanation, "hotkey"):
+		return "hotkey"
+	case strings.Contains(explanation, "markup"):
+		return "markup"
+	case strings.Contains(explanation, "machine translation") ||
+		strings.Contains(explanation, "untranslated"):
+		return "mt-artifact"
+	case strings.Contains(explanation, "mistranslat"):
+		return "mistranslation"
+	}
+	return "other"
+}
+
+// categoryBreakdown summarizes review issues as category counts in
+// descending order, such as "terminology 3, grammar 1", and returns an
+// empty string if there are no issues.
+func categoryBreakdown(issues []ReviewIssue) string {
+	counts := make(map[string]int)
+	var names []string
+	for _, issue := range issues {
+		category := reviewIssueCategory(issue)
+		if counts[category] == 0 {
+			names = append(names, category)
+		}
+		counts[category]++
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return counts[names[i]] > counts[names[j]]
+	})
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %d", name, counts[name]))
+	}
+	return strings.Join(parts, ", ")
+}
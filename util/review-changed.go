@@ -0,0 +1,68 @@
+package util
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// extractChangedEntries writes the entries of a po file whose
+// translations were added or changed since the given ref to a
+// temporary po file, and returns its name and the number of extracted
+// entries.
+func extractChangedEntries(poFile, ref string) (string, int, error) {
+	oldFile, err := loadPoFileOrBlob(ref + ":" + poFile)
+	if err != nil {
+		// The po file does not exist in ref, all entries are new.
+		oldFile = &PoFile{}
+	}
+	newFile, err := LoadPoFile(poFile)
+	if err != nil {
+		return "", 0, err
+	}
+	changed := make(map[string]bool)
+	for _, diff := range DiffPoFiles(oldFile, newFile) {
+		if diff.Change == PoDiffAdded || diff.Change == PoDiffChanged {
+			changed[poEntryKey(diff.MsgCtxt, diff.MsgID)] = true
+		}
+	}
+	subset := PoFile{
+		Header:     newFile.Header,
+		LineEnding: newFile.LineEnding,
+	}
+	for _, entry := range newFile.Entries {
+		if changed[entry.Key()] && !entry.Obsolete {
+			subset.Entries = append(subset.Entries, entry)
+		}
+	}
+	tmpFile, err := ioutil.TempFile("", "pofile")
+	if err != nil {
+		return "", 0, err
+	}
+	tmpFile.Close()
+	if err = subset.Save(tmpFile.Name()); err != nil {
+		os.Remove(tmpFile.Name())
+		return "", 0, err
+	}
+	return tmpFile.Name(), len(changed), nil
+}
+
+// reviewChangedEntries runs the agent to review only the entries of a
+// po file whose translations were added or changed since the given
+// ref, dramatically cutting review cost for small updates.
+func reviewChangedEntries(ctx context.Context, agent *Agent, poFile, locale, ref string) (string, error) {
+	tmpFile, nrChanged, err := extractChangedEntries(poFile, ref)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile)
+	if nrChanged == 0 {
+		log.Infof(`no translations of "%s" changed since "%s"`, poFile, ref)
+		return `{"issues": []}`, nil
+	}
+	log.Infof(`reviewing %d changed translations of "%s" since "%s"`,
+		nrChanged, poFile, ref)
+	return runAgentReviewFile(ctx, agent, tmpFile, locale)
+}
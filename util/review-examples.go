@@ -0,0 +1,133 @@
+package util
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ReviewExamplesDir holds curated few-shot examples for review
+// prompts, one JSON-lines file per locale.
+const ReviewExamplesDir = "po/.review-examples"
+
+// ReviewExample is a curated few-shot example included in review
+// prompts: a bad translation and the issue the agent is expected to
+// report for it.
+type ReviewExample struct {
+	MsgID  string `json:"msgid"`
+	MsgStr string `json:"msgstr"`
+	Issue  string `json:"issue"`
+}
+
+// LoadReviewExamples reads the few-shot examples of a locale, and
+// returns an empty list if there are none.
+func LoadReviewExamples(locale string) ([]ReviewExample, error) {
+	var examples []ReviewExample
+
+	fileName := filepath.Join(ReviewExamplesDir, locale+".jsonl")
+	f, err := os.Open(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		example := ReviewExample{}
+		if err := json.Unmarshal([]byte(line), &example); err != nil {
+			return nil, fmt.Errorf("invalid review example at %s:%d: %s",
+				fileName, lineNo, err)
+		}
+		examples = append(examples, example)
+	}
+	return examples, scanner.Err()
+}
+
+// AddReviewExample appends a few-shot example to the examples file of
+// a locale.
+func AddReviewExample(locale string, example ReviewExample) error {
+	if err := os.MkdirAll(ReviewExamplesDir, 0755); err != nil {
+		return err
+	}
+	buf, err := json.Marshal(&example)
+	if err != nil {
+		return err
+	}
+	fileName := filepath.Join(ReviewExamplesDir, locale+".jsonl")
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(buf, '\n'))
+	return err
+}
+
+// reviewPromptForLocale builds the review prompt of a locale, with
+// curated few-shot examples appended if there are any.
+func reviewPromptForLocale(locale string) string {
+	var sb strings.Builder
+	if prompt, err := configPrompt("review"); err != nil {
+		log.Warn(err)
+		sb.WriteString(defaultReviewPrompt)
+	} else if prompt != "" {
+		sb.WriteString(prompt)
+	} else {
+		sb.WriteString(defaultReviewPrompt)
+	}
+	if note := lockedEntriesPromptNote(filepath.Join(PoDir, locale+".po")); note != "" {
+		sb.WriteString(" ")
+		sb.WriteString(note)
+	}
+	examples, err := LoadReviewExamples(locale)
+	if err != nil {
+		log.Warn(err)
+		return sb.String()
+	}
+	if len(examples) == 0 {
+		return sb.String()
+	}
+	sb.WriteString("\n\nExamples of issues to report:\n")
+	for _, example := range examples {
+		fmt.Fprintf(&sb, "\nmsgid: %s\nmsgstr: %s\nissue: %s\n",
+			example.MsgID, example.MsgStr, example.Issue)
+	}
+	return sb.String()
+}
+
+// CmdAddReviewExample implements agent-run add-review-example sub
+// command.
+func CmdAddReviewExample() bool {
+	locale := FlagReviewExampleLang()
+	example := ReviewExample{
+		MsgID:  FlagReviewExampleMsgID(),
+		MsgStr: FlagReviewExampleMsgStr(),
+		Issue:  FlagReviewExampleIssue(),
+	}
+	if locale == "" || example.MsgID == "" || example.Issue == "" {
+		log.Errorf(`please provide "--lang", "--msgid" and "--issue" options`)
+		return false
+	}
+	if _, err := GetPrettyLocaleName(locale); err != nil {
+		log.Error(err)
+		return false
+	}
+	if err := AddReviewExample(locale, example); err != nil {
+		log.Error(err)
+		return false
+	}
+	return true
+}
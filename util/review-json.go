@@ -0,0 +1,104 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ReviewIssue is one issue reported by the review agent. Category is
+// a name of the issue category taxonomy, see reviewCategories.
+// Sources records the batches or runs an issue was reported in, filled
+// in by AggregateReviewJSON.
+type ReviewIssue struct {
+	MsgID       string   `json:"msgid"`
+	MsgIDPlural string   `json:"msgid_plural,omitempty"`
+	MsgCtxt     string   `json:"msgctxt,omitempty"`
+	MsgStr      string   `json:"msgstr"`
+	Suggestion  string   `json:"suggestion,omitempty"`
+	Explanation string   `json:"explanation,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
+}
+
+// ReviewJSONResult is the structured result of an agent review.
+type ReviewJSONResult struct {
+	Issues []ReviewIssue `json:"issues"`
+}
+
+// ParseReviewJSON extracts the structured review result from agent
+// output. Agents tend to wrap the JSON document in prose or markdown
+// fences, so everything outside the outermost braces is ignored.
+func ParseReviewJSON(data []byte) (*ReviewJSONResult, error) {
+	text := string(data)
+	begin := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if begin < 0 || end < begin {
+		return nil, fmt.Errorf("no JSON document found in review output")
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(text[begin:end+1]), &doc); err != nil {
+		return nil, fmt.Errorf("fail to parse review output: %s", err)
+	}
+	if errs := validateReviewSchema(doc); len(errs) > 0 {
+		return nil, joinSchemaErrors(errs)
+	}
+	result := ReviewJSONResult{}
+	if err := json.Unmarshal([]byte(text[begin:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("fail to parse review output: %s", err)
+	}
+	return &result, nil
+}
+
+// normalizeMsgText normalizes the text of a po message for use as a
+// deduplication key, folding the common string escapes and collapsing
+// whitespace, so entries differing only in formatting merge.
+func normalizeMsgText(text string) string {
+	text = strings.Replace(text, `\n`, "\n", -1)
+	text = strings.Replace(text, `\t`, "\t", -1)
+	text = strings.Replace(text, `\"`, `"`, -1)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// reviewIssueKey returns the deduplication key of one review issue,
+// the normalized (msgctxt, msgid, msgid_plural) tuple, so singular and
+// plural entries with the same msgid do not collide.
+func reviewIssueKey(issue ReviewIssue) string {
+	return strings.Join([]string{
+		normalizeMsgText(issue.MsgCtxt),
+		normalizeMsgText(issue.MsgID),
+		normalizeMsgText(issue.MsgIDPlural),
+	}, "\x00")
+}
+
+// AggregateReviewJSON merges review results of several batches or runs
+// into one, in order, deduplicating issues which report the same
+// entry. The source label of each result records the provenance of
+// the kept issues.
+func AggregateReviewJSON(sources []string, results []*ReviewJSONResult) *ReviewJSONResult {
+	aggregate := &ReviewJSONResult{Issues: []ReviewIssue{}}
+	index := make(map[string]int)
+	for i, result := range results {
+		source := ""
+		if i < len(sources) {
+			source = sources[i]
+		}
+		for _, issue := range result.Issues {
+			key := reviewIssueKey(issue)
+			if at, ok := index[key]; ok {
+				if source != "" {
+					kept := &aggregate.Issues[at]
+					kept.Sources = append(kept.Sources, source)
+				}
+				continue
+			}
+			if source != "" {
+				issue.Sources = []string{source}
+			}
+			index[key] = len(aggregate.Issues)
+			aggregate.Issues = append(aggregate.Issues, issue)
+		}
+	}
+	return aggregate
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestParseReviewJSON(t *testing.T) {
+	output := "Here is my review:\n" +
+		"```json\n" +
+		`{
+  "issues": [
+    {
+      "msgid": "apple",
+      "msgstr": "香蕉",
+      "suggestion": "苹果",
+      "severity": "error"
+    }
+  ]
+}` + "\n```\nLet me know if you have questions.\n"
+	result, err := ParseReviewJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("fail to parse review output: %s", err)
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("parsed %d issues, expect 1", len(result.Issues))
+	}
+	issue := result.Issues[0]
+	if issue.MsgID != "apple" || issue.Suggestion != "苹果" || issue.Severity != "error" {
+		t.Errorf("bad issue: %+v", issue)
+	}
+}
+
+func TestParseReviewJSONErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{"no json document", "all translations look good to me"},
+		{"broken json", `{"issues": [`},
+		{"bad schema", `{"issues": [{"msgstr": "no msgid here"}]}`},
+	} {
+		if _, err := ParseReviewJSON([]byte(tc.data)); err == nil {
+			t.Errorf("no error for %s", tc.name)
+		}
+	}
+}
+
+func TestAggregateReviewJSON(t *testing.T) {
+	results := []*ReviewJSONResult{
+		{
+			Issues: []ReviewIssue{
+				{MsgID: "apple", MsgStr: "香蕉", Suggestion: "苹果"},
+				{MsgID: "Status", MsgCtxt: "column", MsgStr: "状态报告"},
+			},
+		},
+		{
+			Issues: []ReviewIssue{
+				// Same entry as in batch 1, modulo formatting of the
+				// msgid, must merge into one issue.
+				{MsgID: "apple ", MsgStr: "香蕉", Suggestion: "苹果啊"},
+				// Same msgid but no msgctxt, must stay distinct.
+				{MsgID: "Status", MsgStr: "状态"},
+			},
+		},
+	}
+	aggregate := AggregateReviewJSON([]string{"batch-1", "batch-2"}, results)
+	if len(aggregate.Issues) != 3 {
+		t.Fatalf("aggregated %d issues, expect 3: %+v",
+			len(aggregate.Issues), aggregate.Issues)
+	}
+	first := aggregate.Issues[0]
+	if first.Suggestion != "苹果" {
+		t.Errorf("first reported issue not kept: %+v", first)
+	}
+	if len(first.Sources) != 2 || first.Sources[0] != "batch-1" ||
+		first.Sources[1] != "batch-2" {
+		t.Errorf("bad provenance of merged issue: %v", first.Sources)
+	}
+	if aggregate.Issues[1].MsgCtxt != "column" {
+		t.Errorf("contexted issue lost: %+v", aggregate.Issues[1])
+	}
+	if len(aggregate.Issues[2].Sources) != 1 ||
+		aggregate.Issues[2].Sources[0] != "batch-2" {
+		t.Errorf("bad provenance of distinct issue: %v", aggregate.Issues[2].Sources)
+	}
+}
+
+func TestAggregateReviewJSONWithoutSources(t *testing.T) {
+	results := []*ReviewJSONResult{
+		{Issues: []ReviewIssue{{MsgID: "apple", MsgStr: "香蕉"}}},
+		{Issues: []ReviewIssue{{MsgID: "apple", MsgStr: "香蕉"}}},
+	}
+	aggregate := AggregateReviewJSON(nil, results)
+	if len(aggregate.Issues) != 1 {
+		t.Fatalf("aggregated %d issues, expect 1", len(aggregate.Issues))
+	}
+	if len(aggregate.Issues[0].Sources) != 0 {
+		t.Errorf("unexpected provenance: %v", aggregate.Issues[0].Sources)
+	}
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// reviewProgress records the results of completed review batches, so a
+// review which fails halfway can be resumed without re-sending the
+// finished batches to the agent.
+type reviewProgress struct {
+	// Locale of the po file being reviewed.
+	Locale string `json:"locale"`
+
+	// BatchSize used to split entries when the review started.
+	BatchSize int `json:"batch_size"`
+
+	// NrBatches is the total number of batches of the review.
+	NrBatches int `json:"nr_batches"`
+
+	// Results maps completed batch numbers (starting from 0) to
+	// their review results.
+	Results map[int]*ReviewJSONResult `json:"results"`
+}
+
+func reviewProgressFile() string {
+	return filepath.Join(PoDir, ".review-progress.json")
+}
+
+// loadReviewProgress reads the progress file of a former review of the
+// given locale, and returns nil if there is none or it was written
+// with different batch settings.
+func loadReviewProgress(locale string, batchSize, nrBatches int) *reviewProgress {
+	buf, err := ioutil.ReadFile(reviewProgressFile())
+	if err != nil {
+		return nil
+	}
+	progress := reviewProgress{}
+	if err = json.Unmarshal(buf, &progress); err != nil {
+		return nil
+	}
+	if progress.Locale != locale ||
+		progress.BatchSize != batchSize ||
+		progress.NrBatches != nrBatches {
+		return nil
+	}
+	return &progress
+}
+
+// saveReviewProgress writes the progress file of the running review.
+func saveReviewProgress(progress *reviewProgress) error {
+	buf, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(reviewProgressFile(), append(buf, '\n'), 0644)
+}
+
+// removeReviewProgress removes the progress file after a review
+// completes successfully.
+func removeReviewProgress() {
+	os.Remove(reviewProgressFile())
+}
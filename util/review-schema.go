@@ -0,0 +1,123 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// jsonTypeName returns the JSON name of the type of a decoded value,
+// for use in schema violation messages.
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// validateReviewSchema validates a decoded review document against the
+// expected schema, and reports one error per violating field, naming
+// the field, what was received, and what is expected.
+func validateReviewSchema(doc interface{}) []error {
+	var errs []error
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf(
+			"review output is %s, expect an object with an \"issues\" array",
+			jsonTypeName(doc))}
+	}
+	rawIssues, ok := root["issues"]
+	if !ok {
+		return []error{fmt.Errorf(
+			`review output has no "issues" field, expect an array of issues`)}
+	}
+	issues, ok := rawIssues.([]interface{})
+	if !ok {
+		return []error{fmt.Errorf(
+			`field "issues" is %s, expect an array of issues`,
+			jsonTypeName(rawIssues))}
+	}
+	for i, rawIssue := range issues {
+		issue, ok := rawIssue.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf(
+				"issues[%d] is %s, expect an object",
+				i, jsonTypeName(rawIssue)))
+			continue
+		}
+		for _, field := range []string{"msgid", "msgstr"} {
+			value, ok := issue[field]
+			if !ok {
+				errs = append(errs, fmt.Errorf(
+					`issues[%d] has no "%s" field`, i, field))
+				continue
+			}
+			if _, ok = value.(string); !ok {
+				errs = append(errs, fmt.Errorf(
+					`issues[%d].%s is %s (%v), expect a string`,
+					i, field, jsonTypeName(value), value))
+			}
+		}
+		for _, field := range []string{"msgid_plural", "msgctxt", "suggestion", "explanation"} {
+			if value, ok := issue[field]; ok {
+				if _, ok = value.(string); !ok {
+					errs = append(errs, fmt.Errorf(
+						`issues[%d].%s is %s (%v), expect a string`,
+						i, field, jsonTypeName(value), value))
+				}
+			}
+		}
+		if value, ok := issue["severity"]; ok {
+			severity, isString := value.(string)
+			if !isString {
+				errs = append(errs, fmt.Errorf(
+					`issues[%d].severity is %s (%v), expect "error" or "warning"`,
+					i, jsonTypeName(value), value))
+			} else if severity != "error" && severity != "warning" {
+				errs = append(errs, fmt.Errorf(
+					`issues[%d].severity is "%s", expect "error" or "warning"`,
+					i, severity))
+			}
+		}
+		if value, ok := issue["category"]; ok {
+			category, isString := value.(string)
+			if !isString {
+				errs = append(errs, fmt.Errorf(
+					`issues[%d].category is %s (%v), expect one of: %s`,
+					i, jsonTypeName(value), value,
+					strings.Join(reviewCategories(), ", ")))
+			} else if !isReviewCategory(category) {
+				errs = append(errs, fmt.Errorf(
+					`issues[%d].category is "%s", expect one of: %s`,
+					i, category, strings.Join(reviewCategories(), ", ")))
+			}
+		}
+	}
+	return errs
+}
+
+// joinSchemaErrors turns schema violations into one error message,
+// listing at most three violations.
+func joinSchemaErrors(errs []error) error {
+	var messages []string
+	for i, err := range errs {
+		if i == 3 {
+			messages = append(messages,
+				fmt.Sprintf("and %d more problems", len(errs)-i))
+			break
+		}
+		messages = append(messages, err.Error())
+	}
+	return fmt.Errorf("invalid review output: %s", strings.Join(messages, "; "))
+}
@@ -0,0 +1,131 @@
+package util
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ScoreStrategy turns review issues into a 0-100 quality score, so
+// teams can tune how aggressively issues affect the score.
+type ScoreStrategy interface {
+	// Name of the strategy, used in config and reports.
+	Name() string
+
+	// Score computes the 0-100 score of a review over nrEntries
+	// entries which reported the given issues.
+	Score(nrEntries int, issues []ReviewIssue) int
+}
+
+// perEntryDeduction is the classic model: every issue costs 3 points.
+type perEntryDeduction struct{}
+
+func (v perEntryDeduction) Name() string { return "per-entry" }
+
+func (v perEntryDeduction) Score(nrEntries int, issues []ReviewIssue) int {
+	return clampScore(100 - 3*len(issues))
+}
+
+// severityWeighted deducts more points for severe issues.
+type severityWeighted struct{}
+
+func (v severityWeighted) Name() string { return "severity-weighted" }
+
+func (v severityWeighted) Score(nrEntries int, issues []ReviewIssue) int {
+	score := 100
+	for _, issue := range issues {
+		switch issue.Severity {
+		case "error":
+			score -= 5
+		case "warning":
+			score -= 2
+		default:
+			score -= 3
+		}
+	}
+	return clampScore(score)
+}
+
+// categoryWeighted deducts points per issue category, so teams can
+// focus the score on the problem classes they care about. Weights are
+// tunable with config variables "review.category_weights.<category>".
+type categoryWeighted struct{}
+
+func (v categoryWeighted) Name() string { return "category-weighted" }
+
+func (v categoryWeighted) Score(nrEntries int, issues []ReviewIssue) int {
+	score := 100
+	for _, issue := range issues {
+		score -= categoryWeight(reviewIssueCategory(issue))
+	}
+	return clampScore(score)
+}
+
+// categoryWeight returns the score deduction of one issue category.
+func categoryWeight(category string) int {
+	if weight := viper.GetInt("review.category_weights." + category); weight > 0 {
+		return weight
+	}
+	switch category {
+	case "placeholder":
+		return 5
+	case "omission":
+		return 4
+	case "terminology":
+		return 3
+	case "grammar":
+		return 2
+	case "punctuation":
+		return 1
+	}
+	return 3
+}
+
+// issueDensity scores by issues per 100 entries, so large po files are
+// not punished for their size.
+type issueDensity struct{}
+
+func (v issueDensity) Name() string { return "issues-per-100-entries" }
+
+func (v issueDensity) Score(nrEntries int, issues []ReviewIssue) int {
+	if nrEntries == 0 {
+		return 100
+	}
+	density := float64(len(issues)) * 100 / float64(nrEntries)
+	return clampScore(100 - int(density*3))
+}
+
+func clampScore(score int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
+
+// SelectScoreStrategy returns the scoring strategy selected by config
+// variable "review.scoring", and defaults to the per-entry model.
+func SelectScoreStrategy() ScoreStrategy {
+	name := viper.GetString("review.scoring")
+	for _, strategy := range []ScoreStrategy{
+		perEntryDeduction{},
+		severityWeighted{},
+		categoryWeighted{},
+		issueDensity{},
+	} {
+		if strategy.Name() == name {
+			return strategy
+		}
+	}
+	if name != "" {
+		log.Warnf(`unknown scoring strategy "%s", using "per-entry"`, name)
+	}
+	return perEntryDeduction{}
+}
+
+// CalculateReviewScore computes the 0-100 review score with the
+// configured scoring strategy.
+func CalculateReviewScore(nrEntries int, issues []ReviewIssue) int {
+	return SelectScoreStrategy().Score(nrEntries, issues)
+}
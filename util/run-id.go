@@ -0,0 +1,59 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	runID         string
+	runIDOnce     sync.Once
+	runIDHookOnce sync.Once
+)
+
+// RunID returns the unique ID of the current agent-run invocation. The
+// ID is taken from option "--run-id" to resume or associate artifacts
+// of an earlier run, and generated from the start time plus a random
+// suffix otherwise.
+func RunID() string {
+	runIDOnce.Do(func() {
+		if id := FlagRunID(); id != "" {
+			runID = id
+			return
+		}
+		runID = time.Now().Format("20060102-150405")
+		buf := make([]byte, 3)
+		if _, err := rand.Read(buf); err == nil {
+			runID += "-" + hex.EncodeToString(buf)
+		}
+	})
+	return runID
+}
+
+// runIDHook is a logrus hook which stamps every log line with the run
+// ID, so logs can be correlated with the JSON artifacts of the same
+// invocation.
+type runIDHook struct{}
+
+// Levels implements the logrus.Hook interface.
+func (v *runIDHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire implements the logrus.Hook interface.
+func (v *runIDHook) Fire(entry *log.Entry) error {
+	entry.Data["run"] = RunID()
+	return nil
+}
+
+// installRunIDHook turns on run ID stamping of log lines. Called by
+// agent-run commands, which produce artifacts worth correlating.
+func installRunIDHook() {
+	runIDHookOnce.Do(func() {
+		log.AddHook(&runIDHook{})
+	})
+}
@@ -0,0 +1,292 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// serverRun is one operation triggered over the HTTP API. Output
+// written by the run is buffered, so clients can stream it while the
+// run is still going.
+type serverRun struct {
+	ID     int       `json:"id"`
+	Action string    `json:"action"`
+	File   string    `json:"file,omitempty"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end,omitempty"`
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write buffers run output for streaming clients.
+func (v *serverRun) Write(p []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.buf.Write(p)
+}
+
+// output returns the buffered output beyond the given offset.
+func (v *serverRun) output(offset int) []byte {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if offset >= v.buf.Len() {
+		return nil
+	}
+	return append([]byte{}, v.buf.Bytes()[offset:]...)
+}
+
+// snapshot returns a copy of the run state, safe to encode while the
+// run goroutine is still updating it.
+func (v *serverRun) snapshot() *serverRun {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return &serverRun{
+		ID:     v.ID,
+		Action: v.Action,
+		File:   v.File,
+		Status: v.Status,
+		Error:  v.Error,
+		Start:  v.Start,
+		End:    v.End,
+	}
+}
+
+// poServer drives agent operations on the underlying repository over
+// HTTP. Runs are executed one at a time, because they chdir into and
+// modify the same workspace.
+type poServer struct {
+	ctx context.Context
+
+	mu     sync.Mutex
+	nextID int
+	runs   map[int]*serverRun
+
+	runnerMu sync.Mutex
+}
+
+// startRun registers a run and executes it in the background. The run
+// function writes its log output to the run buffer.
+func (v *poServer) startRun(action, file string, run func(r *serverRun) error) *serverRun {
+	v.mu.Lock()
+	v.nextID++
+	r := &serverRun{
+		ID:     v.nextID,
+		Action: action,
+		File:   file,
+		Status: "running",
+		Start:  time.Now(),
+	}
+	v.runs[r.ID] = r
+	v.mu.Unlock()
+
+	go func() {
+		// Serialize runs: they work on the same repository.
+		v.runnerMu.Lock()
+		defer v.runnerMu.Unlock()
+
+		// Mirror log output into the run buffer for streaming.
+		log.SetOutput(io.MultiWriter(os.Stderr, r))
+		defer log.SetOutput(os.Stderr)
+
+		err := run(r)
+		// The run state is guarded by the run's own mutex, which
+		// readers such as handleRuns take as well.
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.End = time.Now()
+		if err != nil {
+			r.Status = "failed"
+			r.Error = err.Error()
+		} else {
+			r.Status = "ok"
+		}
+	}()
+	return r
+}
+
+// getRun looks up a run by the trailing id of the request path.
+func (v *poServer) getRun(path, prefix string) *serverRun {
+	rest := strings.TrimPrefix(path, prefix)
+	rest = strings.TrimSuffix(rest, "/output")
+	id, err := strconv.Atoi(rest)
+	if err != nil {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.runs[id]
+}
+
+// writeJSON renders an API response.
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// handleTrigger starts an update-pot, translate, or review run.
+func (v *poServer) handleTrigger(w http.ResponseWriter, r *http.Request, action string) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed,
+			map[string]string{"error": "POST required"})
+		return
+	}
+	var (
+		file   string
+		locale string
+	)
+	if action != "update-pot" {
+		file = r.FormValue("file")
+		locale = strings.TrimSuffix(filepath.Base(file), ".po")
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			writeJSON(w, http.StatusBadRequest,
+				map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	run := v.startRun(action, file, func(r *serverRun) error {
+		switch action {
+		case "update-pot":
+			if !CmdUpdatePot() {
+				return fmt.Errorf("fail to update pot file")
+			}
+			return nil
+		case "translate":
+			agent, err := NewAgentFromFlags()
+			if err != nil {
+				return err
+			}
+			return RunAgentTranslate(v.ctx, agent, locale)
+		case "review":
+			agent, err := NewAgentFromFlags()
+			if err != nil {
+				return err
+			}
+			report, err := RunAgentReview(v.ctx, agent,
+				filepath.Join(PoDir, locale+".po"))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(r, report)
+			return nil
+		}
+		return fmt.Errorf(`unknown action "%s"`, action)
+	})
+	writeJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// handleRuns lists runs, shows one run, or streams its output as
+// server-sent events.
+func (v *poServer) handleRuns(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/api/runs/"
+	if r.URL.Path == "/api/runs" || r.URL.Path == prefix {
+		v.mu.Lock()
+		runs := make([]*serverRun, 0, len(v.runs))
+		for _, run := range v.runs {
+			runs = append(runs, run.snapshot())
+		}
+		v.mu.Unlock()
+		sort.Slice(runs, func(i, j int) bool { return runs[i].ID < runs[j].ID })
+		writeJSON(w, http.StatusOK, runs)
+		return
+	}
+	run := v.getRun(r.URL.Path, prefix)
+	if run == nil {
+		writeJSON(w, http.StatusNotFound,
+			map[string]string{"error": "no such run"})
+		return
+	}
+	if !strings.HasSuffix(r.URL.Path, "/output") {
+		writeJSON(w, http.StatusOK, run.snapshot())
+		return
+	}
+
+	// Stream run output as server-sent events.
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError,
+			map[string]string{"error": "streaming unsupported"})
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	offset := 0
+	for {
+		chunk := run.output(offset)
+		offset += len(chunk)
+		for _, line := range strings.Split(string(chunk), "\n") {
+			if line != "" {
+				fmt.Fprintf(w, "data: %s\n\n", line)
+			}
+		}
+		flusher.Flush()
+
+		status := run.snapshot().Status
+		if status != "running" && run.output(offset) == nil {
+			fmt.Fprintf(w, "event: done\ndata: %s\n\n", status)
+			flusher.Flush()
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-v.ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// CmdServe implements serve sub command, exposing agent operations of
+// the underlying repository over HTTP.
+func CmdServe(ctx context.Context) bool {
+	server := &poServer{
+		ctx:  ctx,
+		runs: make(map[int]*serverRun),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/update-pot", func(w http.ResponseWriter, r *http.Request) {
+		server.handleTrigger(w, r, "update-pot")
+	})
+	mux.HandleFunc("/api/translate", func(w http.ResponseWriter, r *http.Request) {
+		server.handleTrigger(w, r, "translate")
+	})
+	mux.HandleFunc("/api/review", func(w http.ResponseWriter, r *http.Request) {
+		server.handleTrigger(w, r, "review")
+	})
+	mux.HandleFunc("/api/runs", server.handleRuns)
+	mux.HandleFunc("/api/runs/", server.handleRuns)
+
+	httpServer := &http.Server{
+		Addr:    FlagServeListen(),
+		Handler: mux,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+	log.Infof(`serving agent operations on "%s"`, FlagServeListen())
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Error(err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	interruptOnce sync.Once
+	interruptCtx  context.Context
+)
+
+// InterruptContext returns a context which is canceled when the user
+// hits Ctrl-C, so a long agent run can terminate its child processes
+// and save its state, instead of leaving orphaned processes and
+// half-written po files behind. A second Ctrl-C exits immediately.
+func InterruptContext() context.Context {
+	interruptOnce.Do(func() {
+		var cancel context.CancelFunc
+
+		interruptCtx, cancel = context.WithCancel(context.Background())
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-c
+			log.Warn("interrupted, terminating agent processes, press Ctrl-C again to exit at once")
+			cancel()
+			<-c
+			os.Exit(1)
+		}()
+	})
+	return interruptCtx
+}
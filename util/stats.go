@@ -0,0 +1,170 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/git-l10n/git-po-helper/data"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// PoStats holds translation progress of one po file.
+type PoStats struct {
+	Locale       string `json:"locale"`
+	NativeName   string `json:"native_name,omitempty"`
+	FileName     string `json:"filename"`
+	Translated   int    `json:"translated"`
+	Fuzzy        int    `json:"fuzzy"`
+	Untranslated int    `json:"untranslated"`
+	Total        int    `json:"total"`
+
+	// PotDelta is the number of entries in po/git.pot which are
+	// missing from the po file, or negative if the po file has more
+	// entries than the pot file.
+	PotDelta int `json:"pot_delta"`
+}
+
+// displayName shows the locale together with its native language name.
+func (v *PoStats) displayName() string {
+	if v.NativeName == "" {
+		return v.Locale
+	}
+	return fmt.Sprintf("%s (%s)", v.Locale, v.NativeName)
+}
+
+// Percent returns percentage of completeness of a po file.
+func (v *PoStats) Percent() float64 {
+	if v.Total == 0 {
+		return 0
+	}
+	return float64(v.Translated) * 100 / float64(v.Total)
+}
+
+// StatPoFile computes translation progress of a po file.
+func StatPoFile(fileName string) (*PoStats, error) {
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+	stats := PoStats{
+		Locale:     locale,
+		NativeName: data.GetNativeLanguageName(locale),
+		FileName:   fileName,
+	}
+	for _, entry := range poFile.Entries {
+		if entry.Obsolete {
+			continue
+		}
+		stats.Total++
+		switch {
+		case entry.IsFuzzy():
+			stats.Fuzzy++
+		case entry.IsTranslated():
+			stats.Translated++
+		default:
+			stats.Untranslated++
+		}
+	}
+	potFile := filepath.Join(PoDir, GitPot)
+	if Exist(potFile) {
+		if pot, err := LoadPoFile(potFile); err == nil {
+			nrPot := 0
+			for _, entry := range pot.Entries {
+				if !entry.Obsolete {
+					nrPot++
+				}
+			}
+			stats.PotDelta = nrPot - stats.Total
+		}
+	}
+	return &stats, nil
+}
+
+// CmdStats implements stats sub command.
+func CmdStats(args ...string) bool {
+	var (
+		allStats []*PoStats
+		ret      = true
+	)
+
+	if len(args) == 0 {
+		args = listPoFiles()
+	}
+	if len(args) == 0 {
+		log.Errorf(`cannot find any ".po" files for stats`)
+		return false
+	}
+	sort.Strings(args)
+	for _, fileName := range args {
+		if fileName == "-" {
+			stats, err := StatPoFile("-")
+			if err != nil {
+				log.Error(err)
+				ret = false
+				continue
+			}
+			allStats = append(allStats, stats)
+			continue
+		}
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		stats, err := StatPoFile(filepath.Join(PoDir, locale+".po"))
+		if err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		allStats = append(allStats, stats)
+	}
+	if len(allStats) == 0 {
+		return false
+	}
+	// Sort languages with proper collation instead of byte-wise.
+	collator := collate.New(language.Und, collate.IgnoreCase)
+	sort.SliceStable(allStats, func(i, j int) bool {
+		return collator.CompareString(allStats[i].displayName(), allStats[j].displayName()) < 0
+	})
+	switch FlagStatsFormat() {
+	case "json":
+		buf, err := json.MarshalIndent(allStats, "", "  ")
+		if err != nil {
+			log.Error(err)
+			return false
+		}
+		fmt.Println(string(buf))
+	case "markdown":
+		fmt.Println("| Language | Translated | Fuzzy | Untranslated | Completeness | Pot delta |")
+		fmt.Println("|----------|-----------:|------:|-------------:|-------------:|----------:|")
+		for _, stats := range allStats {
+			fmt.Printf("| %s | %d | %d | %d | %.1f%% | %d |\n",
+				stats.displayName(), stats.Translated, stats.Fuzzy,
+				stats.Untranslated, stats.Percent(), stats.PotDelta)
+		}
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "Language\tTranslated\tFuzzy\tUntranslated\tCompleteness\tPot delta")
+		for _, stats := range allStats {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f%%\t%d\n",
+				stats.displayName(), stats.Translated, stats.Fuzzy,
+				stats.Untranslated, stats.Percent(), stats.PotDelta)
+		}
+		w.Flush()
+	default:
+		log.Errorf(`unknown stats format "%s", available formats: table, json, markdown`,
+			FlagStatsFormat())
+		return false
+	}
+	return ret
+}
@@ -0,0 +1,231 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// teamsFile returns the default location of the TEAMS file.
+func teamsFile() string {
+	return filepath.Join("po", "TEAMS")
+}
+
+// teamLanguageCode returns the language code of a team, stripping the
+// pretty name, e.g. "zh_CN (Simplified Chinese)" gives "zh_CN".
+func teamLanguageCode(language string) string {
+	if fields := strings.Fields(language); len(fields) > 0 {
+		return fields[0]
+	}
+	return language
+}
+
+// formatUser renders a user in the TEAMS format, obfuscating the email
+// the way the file does.
+func formatUser(user User) string {
+	return fmt.Sprintf("%s <%s>", user.Name,
+		strings.Replace(user.Email, "@", " AT ", 1))
+}
+
+// formatTeam renders one team in the canonical TEAMS format.
+func formatTeam(team Team) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Language:\t%s\n", team.Language)
+	if team.Repository != "" {
+		fmt.Fprintf(&sb, "Repository:\t%s\n", team.Repository)
+	}
+	fmt.Fprintf(&sb, "Leader:\t\t%s\n", formatUser(team.Leader))
+	for i, member := range team.Members {
+		if i == 0 {
+			fmt.Fprintf(&sb, "Members:\t%s\n", formatUser(member))
+		} else {
+			fmt.Fprintf(&sb, "\t\t%s\n", formatUser(member))
+		}
+	}
+	return sb.String()
+}
+
+// readTeamsHead returns the free-form introduction of the TEAMS file,
+// everything before the first "Language:" line, which a rewrite of the
+// file must preserve.
+func readTeamsHead(fileName string) (string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Language:") {
+			break
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String(), scanner.Err()
+}
+
+// saveTeams writes the TEAMS file with its original introduction and
+// the given teams, one blank line between teams.
+func saveTeams(fileName, head string, teams []Team) error {
+	var sb strings.Builder
+
+	sb.WriteString(head)
+	for i, team := range teams {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(formatTeam(team))
+	}
+	return ioutil.WriteFile(fileName, []byte(sb.String()), 0644)
+}
+
+// sortTeams sorts teams by language code, as the TEAMS file requires.
+func sortTeams(teams []Team) {
+	sort.SliceStable(teams, func(i, j int) bool {
+		return teamLanguageCode(teams[i].Language) < teamLanguageCode(teams[j].Language)
+	})
+}
+
+// CmdTeamCheck implements team check sub command. Each class of
+// problems gets its own gate, so CI can tell syntax errors from
+// ordering problems.
+func CmdTeamCheck() bool {
+	ret := true
+
+	teams, errs := ParseTeams("")
+	syntaxOK := len(errs) == 0
+	ReportGate("team/syntax", syntaxOK, "%d teams", len(teams))
+	for _, err := range errs {
+		log.Error(err)
+	}
+	if !syntaxOK {
+		ret = false
+	}
+
+	seen := make(map[string]bool)
+	duplicateOK := true
+	for _, team := range teams {
+		code := teamLanguageCode(team.Language)
+		if seen[code] {
+			log.Errorf(`duplicate team for language "%s"`, code)
+			duplicateOK = false
+		}
+		seen[code] = true
+	}
+	ReportGate("team/duplicate", duplicateOK, "%d teams", len(teams))
+	if !duplicateOK {
+		ret = false
+	}
+
+	sortedOK := true
+	for i := 1; i < len(teams); i++ {
+		prev := teamLanguageCode(teams[i-1].Language)
+		next := teamLanguageCode(teams[i].Language)
+		if prev > next {
+			log.Errorf(`teams are not sorted: "%s" comes after "%s"`, next, prev)
+			sortedOK = false
+		}
+	}
+	ReportGate("team/sorted", sortedOK, "%d teams", len(teams))
+	if !sortedOK {
+		ret = false
+	}
+
+	return ret
+}
+
+// CmdTeamAdd implements team add sub command.
+func CmdTeamAdd() bool {
+	language := FlagTeamLanguage()
+	if language == "" {
+		log.Errorf(`option "--language" is required to add a team`)
+		return false
+	}
+	code := teamLanguageCode(language)
+	if _, err := GetPrettyLocaleName(code); err != nil {
+		log.Error(err)
+		return false
+	}
+	leader, err := parseUser(FlagTeamLeader())
+	if err != nil {
+		log.Errorf(`bad leader: %s`, err)
+		return false
+	}
+	team := Team{
+		Language:   language,
+		Repository: FlagTeamRepository(),
+		Leader:     leader,
+	}
+	for _, member := range FlagTeamMembers() {
+		user, err := parseUser(member)
+		if err != nil {
+			log.Errorf(`bad member: %s`, err)
+			return false
+		}
+		team.Members = append(team.Members, user)
+	}
+
+	teams, errs := ParseTeams("")
+	if len(errs) != 0 {
+		for _, err := range errs {
+			log.Error(err)
+		}
+		log.Errorf(`fix the syntax of "%s" before adding a team`, teamsFile())
+		return false
+	}
+	for _, t := range teams {
+		if teamLanguageCode(t.Language) == code {
+			log.Errorf(`team for language "%s" already exists`, code)
+			return false
+		}
+	}
+	head, err := readTeamsHead(teamsFile())
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	teams = append(teams, team)
+	sortTeams(teams)
+	if err := saveTeams(teamsFile(), head, teams); err != nil {
+		log.Error(err)
+		return false
+	}
+	log.Infof(`added team for language "%s" to "%s"`, code, teamsFile())
+	return true
+}
+
+// CmdTeamFormat implements team format sub command, rewriting the
+// TEAMS file in canonical format with teams sorted by language code.
+func CmdTeamFormat() bool {
+	teams, errs := ParseTeams("")
+	if len(errs) != 0 {
+		for _, err := range errs {
+			log.Error(err)
+		}
+		log.Errorf(`fix the syntax of "%s" before formatting`, teamsFile())
+		return false
+	}
+	head, err := readTeamsHead(teamsFile())
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	sortTeams(teams)
+	if err := saveTeams(teamsFile(), head, teams); err != nil {
+		log.Error(err)
+		return false
+	}
+	log.Infof(`formatted %d teams in "%s"`, len(teams), teamsFile())
+	return true
+}
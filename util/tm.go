@@ -0,0 +1,131 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// tmDir returns the directory holding the compendium po files of the
+// translation memory, one file per language.
+func tmDir() string {
+	return filepath.Join(HelperDir, "tm")
+}
+
+// tmFile returns the compendium file of a locale.
+func tmFile(locale string) string {
+	return filepath.Join(tmDir(), locale+".po")
+}
+
+// CmdTMBuild implements tm build sub command. It harvests translated
+// pairs of a po file from all historical commits into a compendium,
+// which agent-run translate uses to pre-fill matches via msgmerge.
+func CmdTMBuild(args ...string) bool {
+	ret := true
+
+	if len(args) == 0 {
+		args = listPoFiles()
+	}
+	if len(args) == 0 {
+		log.Errorf(`cannot find any ".po" files to harvest`)
+		return false
+	}
+	for _, fileName := range args {
+		locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+		if _, err := GetPrettyLocaleName(locale); err != nil {
+			log.Error(err)
+			ret = false
+			continue
+		}
+		if err := buildCompendium(locale); err != nil {
+			log.Errorf(`fail to build compendium for "%s": %s`, locale, err)
+			ret = false
+		}
+	}
+	return ret
+}
+
+// buildCompendium harvests translated pairs of "po/XX.po" from all
+// historical commits, newest first so the latest translation of a
+// msgid wins, and writes them as a compendium po file.
+func buildCompendium(locale string) error {
+	poFile := filepath.Join(PoDir, locale+".po")
+	cmd := exec.Command("git", "log", "--format=%H", "--", poFile)
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("fail to list commits: %s", err)
+	}
+	commits := strings.Fields(string(buf))
+	if len(commits) == 0 {
+		return fmt.Errorf(`no commits touch "%s"`, poFile)
+	}
+
+	compendium := PoFile{}
+	seen := make(map[string]bool)
+	for _, commit := range commits {
+		historic, err := loadPoFileOrBlob(commit + ":" + poFile)
+		if err != nil {
+			continue
+		}
+		for _, entry := range historic.Entries {
+			if entry.Obsolete || entry.IsFuzzy() || !entry.IsTranslated() {
+				continue
+			}
+			if entry.MsgID == "" {
+				if !seen[""] {
+					seen[""] = true
+					compendium.Entries = append(
+						[]*PoEntry{entry}, compendium.Entries...)
+				}
+				continue
+			}
+			if seen[entry.Key()] {
+				continue
+			}
+			seen[entry.Key()] = true
+			compendium.Entries = append(compendium.Entries, entry)
+		}
+	}
+
+	if err = os.MkdirAll(tmDir(), 0755); err != nil {
+		return err
+	}
+	if err = compendium.Save(tmFile(locale)); err != nil {
+		return err
+	}
+	log.Infof(`harvested %d translations of "%s" from %d commits into "%s"`,
+		len(compendium.Entries)-1, locale, len(commits), tmFile(locale))
+	return nil
+}
+
+// applyCompendium pre-fills matches from the compendium of a locale
+// into its po file via msgmerge, so the agent only works on entries
+// the translation memory cannot fill.
+func applyCompendium(locale string) error {
+	compendium := tmFile(locale)
+	if !Exist(compendium) {
+		return nil
+	}
+	poFile := filepath.Join(PoDir, locale+".po")
+	potFile := filepath.Join(PoDir, GitPot)
+	cmd := exec.Command("msgmerge",
+		"--add-location",
+		"--backup=off",
+		"--compendium",
+		compendium,
+		"-U",
+		poFile,
+		potFile)
+	cmd.Dir = GitRootDir
+	cmd.Stderr = os.Stderr
+	log.Infof(`pre-filling "%s" from compendium "%s"`, poFile, compendium)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf(`fail to apply compendium to "%s": %s`, poFile, err)
+	}
+	return nil
+}
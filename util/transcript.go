@@ -0,0 +1,128 @@
+package util
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// agentTranscript holds the parsed events of one agent log file, for
+// export as a readable Markdown or HTML transcript.
+type agentTranscript struct {
+	FileName string
+	Parser   string
+	Events   []AgentEvent
+	Err      string
+}
+
+// exportTranscripts writes transcripts of parsed agent logs to a file,
+// in Markdown or HTML format chosen by the file extension.
+func exportTranscripts(fileName string, transcripts []*agentTranscript) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".md", ".markdown":
+		for _, t := range transcripts {
+			writeMarkdownTranscript(f, t)
+		}
+	case ".html", ".htm":
+		fmt.Fprintln(f, "<!DOCTYPE html>")
+		fmt.Fprintln(f, "<html><head><meta charset=\"utf-8\">")
+		fmt.Fprintln(f, "<title>Agent transcript</title>")
+		fmt.Fprintln(f, "<style>body { font-family: sans-serif; max-width: 60em; margin: auto; } pre { background: #f6f8fa; padding: 0.5em; overflow-x: auto; }</style>")
+		fmt.Fprintln(f, "</head><body>")
+		for _, t := range transcripts {
+			writeHTMLTranscript(f, t)
+		}
+		fmt.Fprintln(f, "</body></html>")
+	default:
+		return fmt.Errorf(`unknown transcript format of "%s", use a ".md" or ".html" file name`,
+			fileName)
+	}
+	return nil
+}
+
+// writeMarkdownTranscript writes one transcript as Markdown, with one
+// section per assistant turn and tool calls collapsed in details
+// blocks.
+func writeMarkdownTranscript(w io.Writer, t *agentTranscript) {
+	fmt.Fprintf(w, "# Transcript of %s\n\n", t.FileName)
+	if t.Parser != "" {
+		fmt.Fprintf(w, "Agent output format: `%s`\n\n", t.Parser)
+	}
+	turn := 0
+	for _, event := range t.Events {
+		switch event.Type {
+		case AgentEventAssistant:
+			if event.Text == "" {
+				continue
+			}
+			turn++
+			fmt.Fprintf(w, "## Turn %d\n\n%s\n\n", turn, event.Text)
+		case AgentEventTool:
+			if event.Name == "" && event.Text == "" {
+				continue
+			}
+			summary := event.Name
+			if summary == "" {
+				summary = "tool result"
+			}
+			fmt.Fprintf(w, "<details><summary>🔧 %s</summary>\n\n```\n%s\n```\n\n</details>\n\n",
+				summary, event.Text)
+		case AgentEventResult:
+			fmt.Fprintf(w, "## Result\n\n%s\n\n", event.Text)
+		case AgentEventSystem:
+			// System events carry no text to show.
+		}
+	}
+	if t.Err != "" {
+		fmt.Fprintf(w, "## Diagnostics\n\n%s\n\n", t.Err)
+	}
+}
+
+// writeHTMLTranscript writes one transcript as an HTML fragment, in
+// the same layout as the Markdown export.
+func writeHTMLTranscript(w io.Writer, t *agentTranscript) {
+	fmt.Fprintf(w, "<h1>Transcript of %s</h1>\n", html.EscapeString(t.FileName))
+	if t.Parser != "" {
+		fmt.Fprintf(w, "<p>Agent output format: <code>%s</code></p>\n",
+			html.EscapeString(t.Parser))
+	}
+	turn := 0
+	for _, event := range t.Events {
+		switch event.Type {
+		case AgentEventAssistant:
+			if event.Text == "" {
+				continue
+			}
+			turn++
+			fmt.Fprintf(w, "<h2>Turn %d</h2>\n<p>%s</p>\n",
+				turn, html.EscapeString(event.Text))
+		case AgentEventTool:
+			if event.Name == "" && event.Text == "" {
+				continue
+			}
+			summary := event.Name
+			if summary == "" {
+				summary = "tool result"
+			}
+			fmt.Fprintf(w, "<details><summary>🔧 %s</summary><pre>%s</pre></details>\n",
+				html.EscapeString(summary), html.EscapeString(event.Text))
+		case AgentEventResult:
+			fmt.Fprintf(w, "<h2>Result</h2>\n<p>%s</p>\n",
+				html.EscapeString(event.Text))
+		case AgentEventSystem:
+			// System events carry no text to show.
+		}
+	}
+	if t.Err != "" {
+		fmt.Fprintf(w, "<h2>Diagnostics</h2>\n<p>%s</p>\n",
+			html.EscapeString(t.Err))
+	}
+}
@@ -0,0 +1,211 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The TUI dashboard redraws the terminal while agents run, showing the
+// stage of each language, validation gates, counters, and the tail of
+// the streaming agent output in separate panes. It is drawn with plain
+// ANSI escapes, so the module needs no terminal UI dependency.
+
+// tuiRedrawInterval is the pace of dashboard redraws.
+const tuiRedrawInterval = 500 * time.Millisecond
+
+// tuiOutputLines is the number of agent output lines kept in the
+// output pane.
+const tuiOutputLines = 12
+
+// tuiGateLine is one validation or check gate shown on the dashboard.
+type tuiGateLine struct {
+	Name string
+	OK   bool
+}
+
+// tuiDashboard holds the state of the dashboard, fed from the agent
+// run and rendered by a redraw goroutine.
+type tuiDashboard struct {
+	mu       sync.Mutex
+	start    time.Time
+	status   map[string]string
+	order    []string
+	gates    []tuiGateLine
+	counters map[string]int64
+	output   []string
+	partial  string
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+var dashboard *tuiDashboard
+
+// tuiActive tells whether the dashboard is running.
+func tuiActive() bool {
+	return dashboard != nil
+}
+
+// startTUI turns on the dashboard when option "--tui" is given and
+// stdout is a terminal. Log lines are routed into the output pane, so
+// they do not corrupt the screen.
+func startTUI() {
+	if !FlagTUI() || dashboard != nil {
+		return
+	}
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		log.Warnf("option --tui is ignored, stdout is not a terminal")
+		return
+	}
+	dashboard = &tuiDashboard{
+		start:    time.Now(),
+		status:   make(map[string]string),
+		counters: make(map[string]int64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	log.SetOutput(dashboard)
+	go func() {
+		ticker := time.NewTicker(tuiRedrawInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-dashboard.stop:
+				close(dashboard.done)
+				return
+			case <-ticker.C:
+				dashboard.redraw()
+			}
+		}
+	}()
+}
+
+// stopTUI stops the redraw goroutine and clears the screen, so the
+// final scorecard of the run is printed on a normal terminal.
+func stopTUI() {
+	if dashboard == nil {
+		return
+	}
+	close(dashboard.stop)
+	<-dashboard.done
+	log.SetOutput(os.Stderr)
+	fmt.Print("\x1b[2J\x1b[H")
+	dashboard = nil
+}
+
+// Write implements io.Writer, feeding log and agent output lines into
+// the output pane.
+func (v *tuiDashboard) Write(p []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.partial += string(p)
+	for {
+		idx := strings.IndexByte(v.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		v.output = append(v.output, strings.TrimRight(v.partial[:idx], "\r"))
+		v.partial = v.partial[idx+1:]
+	}
+	if len(v.output) > tuiOutputLines {
+		v.output = v.output[len(v.output)-tuiOutputLines:]
+	}
+	return len(p), nil
+}
+
+// agentConsole returns the sink for streaming agent output and
+// diagnostics: the output pane when the dashboard is running, and the
+// given fallback otherwise.
+func agentConsole(fallback io.Writer) io.Writer {
+	if dashboard != nil {
+		return dashboard
+	}
+	return fallback
+}
+
+// setTUIStatus updates the stage shown for one language or batched
+// operation on the dashboard.
+func setTUIStatus(name, stage string) {
+	if dashboard == nil {
+		return
+	}
+	dashboard.mu.Lock()
+	defer dashboard.mu.Unlock()
+	if _, ok := dashboard.status[name]; !ok {
+		dashboard.order = append(dashboard.order, name)
+	}
+	dashboard.status[name] = stage
+}
+
+// tuiReportGate records a finished gate on the validation pane.
+func tuiReportGate(name string, ok bool) {
+	if dashboard == nil {
+		return
+	}
+	dashboard.mu.Lock()
+	defer dashboard.mu.Unlock()
+	dashboard.gates = append(dashboard.gates, tuiGateLine{Name: name, OK: ok})
+}
+
+// tuiAddCounter increments a counter on the counters pane.
+func tuiAddCounter(name string, delta int64) {
+	if dashboard == nil {
+		return
+	}
+	dashboard.mu.Lock()
+	defer dashboard.mu.Unlock()
+	dashboard.counters[name] += delta
+}
+
+// redraw repaints the whole dashboard.
+func (v *tuiDashboard) redraw() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "git-po-helper — run %s — elapsed %s\n",
+		RunID(), time.Since(v.start).Round(time.Second))
+	if len(v.order) > 0 {
+		buf.WriteString("\nProgress:\n")
+		for _, name := range v.order {
+			fmt.Fprintf(&buf, "  %-16s %s\n", name, v.status[name])
+		}
+	}
+	if len(v.gates) > 0 {
+		buf.WriteString("\nGates:\n")
+		gates := v.gates
+		if len(gates) > tuiOutputLines {
+			gates = gates[len(gates)-tuiOutputLines:]
+		}
+		for _, gate := range gates {
+			status := "ok  "
+			if !gate.OK {
+				status = "FAIL"
+			}
+			fmt.Fprintf(&buf, "  %s %s\n", status, gate.Name)
+		}
+	}
+	if len(v.counters) > 0 {
+		buf.WriteString("\nCounters:\n")
+		var names []string
+		for name := range v.counters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "  %-24s %d\n", name, v.counters[name])
+		}
+	}
+	if len(v.output) > 0 {
+		buf.WriteString("\nAgent output:\n")
+		for _, line := range v.output {
+			fmt.Fprintf(&buf, "  %s\n", line)
+		}
+	}
+	fmt.Print("\x1b[2J\x1b[H" + buf.String())
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// xgettextOptions are the canonical options used by Git to generate
+// "po/git.pot" from C sources.
+var xgettextOptions = []string{
+	"--force-po",
+	"--add-comments=TRANSLATORS:",
+	"--msgid-bugs-address=Git Mailing List <git@vger.kernel.org>",
+	"--from-code=UTF-8",
+	"--language=C",
+	"--keyword=_",
+	"--keyword=N_",
+	"--keyword=Q_:1,2",
+}
+
+// CmdUpdatePot implements update-pot sub command. It regenerates
+// "po/git.pot" from the tracked C sources with xgettext, so mechanical
+// pot updates need no agent.
+func CmdUpdatePot() bool {
+	cmd := exec.Command("git", "ls-files", "*.c", "*.h")
+	cmd.Dir = GitRootDir
+	buf, err := cmd.Output()
+	if err != nil {
+		log.Errorf("fail to list source files: %s", err)
+		return false
+	}
+	var sources []string
+	for _, line := range strings.Split(string(buf), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			sources = append(sources, line)
+		}
+	}
+	if len(sources) == 0 {
+		log.Errorf("no C sources found to extract messages from")
+		return false
+	}
+
+	listFile, err := ioutil.TempFile("", "potfiles")
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	defer os.Remove(listFile.Name())
+	if _, err = listFile.WriteString(strings.Join(sources, "\n") + "\n"); err != nil {
+		listFile.Close()
+		log.Error(err)
+		return false
+	}
+	listFile.Close()
+
+	potFile := filepath.Join(PoDir, GitPot)
+	args := append([]string{}, xgettextOptions...)
+	args = append(args,
+		"-o", potFile,
+		"--files-from="+listFile.Name())
+	cmd = exec.Command("xgettext", args...)
+	cmd.Dir = GitRootDir
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		log.Errorf("fail to run xgettext: %s", err)
+		return false
+	}
+	log.Infof(`updated "%s" from %d source files`, potFile, len(sources))
+	if FlagAutoCommit() {
+		if err := autoCommitFiles("l10n: git.pot: update po template", potFile); err != nil {
+			log.Error(err)
+			return false
+		}
+	}
+	return true
+}
@@ -41,5 +41,19 @@ func CmdUpdate(fileName string) bool {
 		log.Errorf(`fail to update "%s": %s`, poFile, err)
 		return false
 	}
-	return CheckPoFile(locale, poFile)
+	if !CheckPoFile(locale, poFile) {
+		return false
+	}
+	if FlagAutoCommit() {
+		message := FlagCommitMessageTemplate()
+		if message == "" {
+			message = "l10n: {lang}: update translations"
+		}
+		message = strings.Replace(message, "{lang}", locale, -1)
+		if err := autoCommitFiles(message, poFile); err != nil {
+			log.Error(err)
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// Validator checks a po file after an agent modified it. Old is the
+// parsed po file from before the agent run, and is nil if the file
+// could not be parsed or did not exist.
+type Validator interface {
+	// Name returns the name used to select the validator in the
+	// "validators" list of the config file.
+	Name() string
+
+	// Validate returns the problems found in the modified po file.
+	Validate(old *PoFile, fileName string) []error
+}
+
+// defaultValidators is the validation chain applied to agent-run
+// results unless the "validators" list of the config file overrides
+// it.
+var defaultValidators = []string{"entry-count", "msgfmt", "utf8"}
+
+// validatorRegistry holds all known validators by name.
+var validatorRegistry = map[string]Validator{
+	"entry-count":       &entryCountValidator{},
+	"msgfmt":            &msgfmtValidator{},
+	"glossary":          &glossaryValidator{},
+	"placeholder-check": &placeholderValidator{},
+	"plural-forms":      &pluralFormsValidator{},
+	"utf8":              &utf8Validator{},
+}
+
+// SelectValidators returns the validation chain configured by the
+// "validators" list of the config file, or the default chain.
+func SelectValidators() ([]Validator, error) {
+	names := viper.GetStringSlice("validators")
+	if len(names) == 0 {
+		names = defaultValidators
+	}
+	var validators []Validator
+	for _, name := range names {
+		validator, ok := validatorRegistry[name]
+		if !ok {
+			return nil, fmt.Errorf(`unknown validator "%s" in config file`, name)
+		}
+		validators = append(validators, validator)
+	}
+	return validators, nil
+}
+
+// runValidators applies the configured validation chain to a po file
+// modified by an agent, records one gate per validator, and returns an
+// error if any validator found problems.
+func runValidators(agent *Agent, old *PoFile, fileName string) error {
+	validators, err := SelectValidators()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, validator := range validators {
+		endSpan := startSpan("agent/validate", "validator", validator.Name(), "file", fileName)
+		errs := validator.Validate(old, fileName)
+		endSpan("problems", len(errs))
+		addCounter("validator.problems", int64(len(errs)))
+		ReportGate("validate/"+validator.Name(), len(errs) == 0,
+			"%d problems", len(errs))
+		for _, err := range errs {
+			log.Errorf("%s: %s", validator.Name(), err)
+		}
+		if len(errs) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf(`agent "%s" produced "%s" which fails %d of %d validators`,
+			agent.Name, fileName, failed, len(validators))
+	}
+	return nil
+}
+
+// entryCountValidator verifies the agent did not add or drop entries.
+type entryCountValidator struct{}
+
+func (v *entryCountValidator) Name() string {
+	return "entry-count"
+}
+
+func (v *entryCountValidator) Validate(old *PoFile, fileName string) []error {
+	if old == nil {
+		return nil
+	}
+	poFile, err := LoadPoFile(fileName)
+	if err != nil {
+		return []error{err}
+	}
+	if len(poFile.Entries) != len(old.Entries) {
+		return []error{fmt.Errorf("entry count changed from %d to %d",
+			len(old.Entries), len(poFile.Entries))}
+	}
+	return nil
+}
+
+// msgfmtValidator verifies the syntax of the po file with msgfmt.
+type msgfmtValidator struct{}
+
+func (v *msgfmtValidator) Name() string {
+	return "msgfmt"
+}
+
+func (v *msgfmtValidator) Validate(old *PoFile, fileName string) []error {
+	errs, ok := checkPoSyntax(fileName)
+	if ok {
+		return nil
+	}
+	return errs
+}
+
+// placeholderValidator verifies format specifiers and placeholders of
+// translations.
+type placeholderValidator struct{}
+
+func (v *placeholderValidator) Name() string {
+	return "placeholder-check"
+}
+
+func (v *placeholderValidator) Validate(old *PoFile, fileName string) []error {
+	errs, ok := checkPlaceholdersInPoFile(fileName)
+	if ok {
+		return nil
+	}
+	return errs
+}
+
+// pluralFormsValidator verifies the Plural-Forms header and the number
+// of plural forms of each entry. It is skipped for files whose name is
+// not a known locale, such as temporary batch files.
+type pluralFormsValidator struct{}
+
+func (v *pluralFormsValidator) Name() string {
+	return "plural-forms"
+}
+
+func (v *pluralFormsValidator) Validate(old *PoFile, fileName string) []error {
+	locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+	if _, err := GetPrettyLocaleName(locale); err != nil {
+		return nil
+	}
+	errs, ok := checkPluralFormsInPoFile(locale, fileName)
+	if ok {
+		return nil
+	}
+	return errs
+}
+
+// glossaryValidator verifies glossary terms are translated
+// consistently. It is skipped for files whose name is not a known
+// locale, such as temporary batch files.
+type glossaryValidator struct{}
+
+func (v *glossaryValidator) Name() string {
+	return "glossary"
+}
+
+func (v *glossaryValidator) Validate(old *PoFile, fileName string) []error {
+	locale := strings.TrimSuffix(filepath.Base(fileName), ".po")
+	if _, err := GetPrettyLocaleName(locale); err != nil {
+		return nil
+	}
+	glossary, err := LoadGlossary(locale)
+	if err != nil || len(glossary) == 0 {
+		return nil
+	}
+	errs, ok := checkGlossaryEntries(glossary, fileName)
+	if ok {
+		return nil
+	}
+	return errs
+}
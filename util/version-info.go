@@ -0,0 +1,110 @@
+package util
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/git-l10n/git-po-helper/version"
+)
+
+// gettextTools are the gettext programs the helper shells out to, in
+// the order they are probed by "version --verbose".
+var gettextTools = []string{
+	"git",
+	"msgfmt",
+	"msgcat",
+	"msgattrib",
+	"msgmerge",
+}
+
+// toolVersion probes an external program and returns the first line of
+// its "--version" output, or a note why probing failed.
+func toolVersion(name string) string {
+	execPath, err := exec.LookPath(name)
+	if err != nil {
+		return "not installed"
+	}
+	out, err := exec.Command(execPath, "--version").Output()
+	if err != nil {
+		return fmt.Sprintf("%s (fail to get version: %s)", execPath, err)
+	}
+	line := strings.SplitN(string(out), "\n", 2)[0]
+	return strings.TrimSpace(line)
+}
+
+// buildCommitAndDate returns the vcs revision and commit time compiled
+// into the binary, available when built from a git checkout with module
+// support.
+func buildCommitAndDate() (string, string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	var commit, date string
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			commit = setting.Value
+		case "vcs.time":
+			date = setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				commit += " (dirty)"
+			}
+		}
+	}
+	return commit, date
+}
+
+// configuredAgentCommands returns the names and commands of the agent
+// CLIs defined in config section "agents", sorted by name.
+func configuredAgentCommands() map[string]string {
+	agents := make(map[string]string)
+	for name := range viper.GetStringMap("agents") {
+		if cmd := viper.GetString("agents." + name + ".cmd"); cmd != "" {
+			agents[name] = strings.Fields(cmd)[0]
+		}
+	}
+	if cmd := viper.GetString("agent.cmd"); cmd != "" {
+		agents["agent.cmd"] = strings.Fields(cmd)[0]
+	}
+	return agents
+}
+
+// CmdVersion implements version sub command. In verbose mode, build
+// info and the versions of external tools are shown, which helps a lot
+// in bug reports about environment-dependent failures.
+func CmdVersion(verbose bool) bool {
+	fmt.Printf("git-po-helper version %s\n", version.Version)
+	if !verbose {
+		return true
+	}
+	commit, date := buildCommitAndDate()
+	if commit != "" {
+		fmt.Printf("build commit: %s\n", commit)
+	}
+	if date != "" {
+		fmt.Printf("build date: %s\n", date)
+	}
+	fmt.Printf("go version: %s (%s/%s)\n",
+		runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	for _, tool := range gettextTools {
+		fmt.Printf("%s: %s\n", tool, toolVersion(tool))
+	}
+	agents := configuredAgentCommands()
+	var names []string
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("agent %s: %s\n", name, toolVersion(agents[name]))
+	}
+	return true
+}